@@ -6,8 +6,14 @@ function __gomod_graph_format() {
 	local formats
 
 	formats=(
+		"csv"
+		"d2"
 		"gif"
+		"graphml"
+		"html"
 		"jpg"
+		"json"
+		"mermaid"
 		"pdf"
 		"png"
 		"ps"
@@ -0,0 +1,173 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// savedGraph is the serialised form of a DepGraph. Modules refer to each other by their index in
+// the Modules list so that modules which are shared between several nodes, for example as a
+// replacement, are restored as a single instance.
+type savedGraph struct {
+	Main         int               `json:"main"`
+	Modules      []savedModule     `json:"modules"`
+	Nodes        []int             `json:"nodes"`
+	Dependencies []savedDependency `json:"dependencies"`
+}
+
+type savedModule struct {
+	Main     bool         `json:"main,omitempty"`
+	Path     string       `json:"path"`
+	Replace  *int         `json:"replace,omitempty"`
+	Version  string       `json:"version,omitempty"`
+	Time     *time.Time   `json:"time,omitempty"`
+	Update   *int         `json:"update,omitempty"`
+	Indirect bool         `json:"indirect,omitempty"`
+	TestOnly bool         `json:"testOnly,omitempty"`
+	GoMod    string       `json:"goMod,omitempty"`
+	Error    *ModuleError `json:"error,omitempty"`
+}
+
+type savedDependency struct {
+	Begin   string `json:"begin"`
+	End     string `json:"end"`
+	Version string `json:"version"`
+	Weight  int    `json:"weight,omitempty"`
+}
+
+// Save writes the DepGraph to the specified writer in a format that can be read back via Load. This
+// allows to cache a dependency graph instead of having to recompute it.
+func (g *DepGraph) Save(w io.Writer) error {
+	saved := savedGraph{}
+
+	indices := map[*Module]int{}
+	var index func(*Module) *int
+	index = func(module *Module) *int {
+		if module == nil {
+			return nil
+		}
+		if idx, ok := indices[module]; ok {
+			return &idx
+		}
+		idx := len(saved.Modules)
+		indices[module] = idx
+		saved.Modules = append(saved.Modules, savedModule{})
+		// Referenced modules are indexed first as doing so may grow the list of saved modules.
+		entry := savedModule{
+			Main:     module.Main,
+			Path:     module.Path,
+			Replace:  index(module.Replace),
+			Version:  module.Version,
+			Time:     module.Time,
+			Update:   index(module.Update),
+			Indirect: module.Indirect,
+			TestOnly: module.TestOnly,
+			GoMod:    module.GoMod,
+			Error:    module.Error,
+		}
+		saved.Modules[idx] = entry
+		return &idx
+	}
+
+	nodes := g.sortedNodes()
+	for _, node := range nodes {
+		saved.Nodes = append(saved.Nodes, *index(node.Module))
+	}
+	saved.Main = indices[g.main.Module]
+	for _, node := range nodes {
+		for _, dep := range node.sortedSuccessors() {
+			saved.Dependencies = append(saved.Dependencies, savedDependency{
+				Begin:   dep.begin,
+				End:     dep.end,
+				Version: dep.version,
+				Weight:  dep.weight,
+			})
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(saved); err != nil {
+		g.logger.WithError(err).Error("Failed to save dependency graph.")
+		return fmt.Errorf("could not save dependency graph: %v", err)
+	}
+	return nil
+}
+
+// Load reads a DepGraph that was previously written via Save from the specified reader. The
+// 'logger' parameter can be 'nil' which will result in no output or logging information to be
+// provided.
+func Load(logger *logrus.Logger, r io.Reader) (*DepGraph, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+	logger.Debug("Loading dependency graph.")
+
+	var saved savedGraph
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return nil, fmt.Errorf("could not load dependency graph: %v", err)
+	}
+
+	modules := make([]*Module, len(saved.Modules))
+	for idx := range saved.Modules {
+		modules[idx] = &Module{}
+	}
+	lookup := func(idx *int) (*Module, error) {
+		if idx == nil {
+			return nil, nil
+		}
+		if *idx < 0 || *idx >= len(modules) {
+			return nil, fmt.Errorf("could not load dependency graph: unknown module index %d", *idx)
+		}
+		return modules[*idx], nil
+	}
+	for idx, module := range saved.Modules {
+		replace, err := lookup(module.Replace)
+		if err != nil {
+			return nil, err
+		}
+		update, err := lookup(module.Update)
+		if err != nil {
+			return nil, err
+		}
+		*modules[idx] = Module{
+			Main:     module.Main,
+			Path:     module.Path,
+			Replace:  replace,
+			Version:  module.Version,
+			Time:     module.Time,
+			Update:   update,
+			Indirect: module.Indirect,
+			TestOnly: module.TestOnly,
+			GoMod:    module.GoMod,
+			Error:    module.Error,
+		}
+	}
+
+	mainModule, err := lookup(&saved.Main)
+	if err != nil {
+		return nil, err
+	}
+	graph := NewGraph(logger, mainModule)
+	for _, idx := range saved.Nodes {
+		module, err := lookup(&idx)
+		if err != nil {
+			return nil, err
+		}
+		graph.AddNode(module)
+	}
+	for _, dep := range saved.Dependencies {
+		begin, end := graph.nodes[dep.Begin], graph.nodes[dep.End]
+		if begin == nil || end == nil {
+			return nil, fmt.Errorf("could not load dependency graph: unknown module in dependency of %q on %q", dep.Begin, dep.End)
+		}
+		dependency := &Dependency{begin: dep.Begin, end: dep.End, version: dep.Version, weight: dep.Weight}
+		begin.successors = append(begin.successors, dependency)
+		end.predecessors = append(end.predecessors, dependency)
+	}
+	return graph, nil
+}
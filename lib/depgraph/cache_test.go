@@ -0,0 +1,56 @@
+package depgraph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SaveLoad(t *testing.T) {
+	moduleFork := &Module{Path: "moduleFork", Version: "v0.3.0", Indirect: true}
+	moduleE := &Module{Path: "moduleE", Version: "v0.1.0", Replace: moduleFork}
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD, moduleE, moduleFork},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+			{"moduleB", "moduleE"},
+			{"moduleE", "moduleFork"},
+		},
+	)
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, graph.Save(buffer))
+	loaded, err := Load(nil, buffer)
+	require.NoError(t, err)
+
+	assert.Equal(t, graph.Edges(), loaded.Edges(), "Should restore all dependencies.")
+	assert.True(t, loaded.Main().Module.Main, "Should restore the main module.")
+	assert.Equal(t, "test/module", loaded.Main().Name(), "Should restore the main module.")
+	for name, node := range graph.Nodes() {
+		if assert.Contains(t, loaded.Nodes(), name) {
+			assert.Equal(t, node.Module, loaded.Nodes()[name].Module, "Should restore module information of %q.", name)
+		}
+	}
+	assert.Equal(t, testModuleC.Replace, loaded.Node("moduleC-fork").Module.Replace, "Should follow replacements.")
+	assert.True(
+		t,
+		loaded.Nodes()["moduleE"].Module.Replace == loaded.Nodes()["moduleFork"].Module,
+		"Should restore replacements by another module of the graph as the same instance.",
+	)
+	assert.Equal(t, "v2.0.0", loaded.Node("moduleC").Successors()[0].RequiredVersion(), "Should restore required versions.")
+}
+
+func Test_LoadInvalid(t *testing.T) {
+	_, err := Load(nil, strings.NewReader("not a graph"))
+	assert.Error(t, err)
+
+	_, err = Load(nil, strings.NewReader(`{"main":1,"modules":[{"path":"test/module","main":true}]}`))
+	assert.Error(t, err, "Should reject references to unknown modules.")
+}
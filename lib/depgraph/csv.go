@@ -0,0 +1,29 @@
+package depgraph
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// WriteEdgesCSV writes all dependencies of the DepGraph to the specified writer in CSV format. The
+// first row is a header with the column names 'from_path', 'from_version', 'to_path' and
+// 'to_version'. Each following row describes a single dependency using the selected versions of
+// the modules on both ends. Modules without a version, like the main module, result in an empty
+// version field.
+func (g *DepGraph) WriteEdgesCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"from_path", "from_version", "to_path", "to_version"}}
+	for _, node := range g.sortedNodes() {
+		for _, dep := range node.sortedSuccessors() {
+			end := g.nodes[dep.end]
+			records = append(records, []string{node.Name(), node.SelectedVersion(), end.Name(), end.SelectedVersion()})
+		}
+	}
+
+	if err := writer.WriteAll(records); err != nil {
+		g.logger.WithError(err).Error("Failed to write CSV edges.")
+		return fmt.Errorf("could not write CSV edges: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteEdgesCSV(t *testing.T) {
+	const expectedOutput = `from_path,from_version,to_path,to_version
+moduleA,v1.0.0,moduleC,v0.2.1
+moduleB,v1.1.0,moduleC,v0.2.1
+moduleC,v0.2.1,moduleD,v2.0.0
+test/module,,moduleA,v1.0.0
+test/module,,moduleB,v1.1.0
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testGraph().WriteEdgesCSV(writer), "Should not fail to write CSV.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected CSV.")
+}
@@ -0,0 +1,283 @@
+package depgraph
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrCycle is returned when an operation requires the DepGraph to be free of dependency cycles.
+var ErrCycle = errors.New("dependency graph contains a cycle")
+
+// FindCycles returns all the elementary cycles that are present in the DepGraph. Each cycle is
+// given as the sequence of modules along which it runs, starting with the module whose path sorts
+// first. The cycles themselves are ordered by their starting module and subsequently by the paths
+// of the modules they traverse, which makes the output deterministic.
+//
+// Cycles are only searched for within the strongly connected components of the DepGraph and are
+// enumerated with Johnson's algorithm, which takes time proportional to the size of the DepGraph for
+// each cycle that is found.
+func (g *DepGraph) FindCycles() [][]*Module {
+	g.logger.Debug("Searching for dependency cycles.")
+
+	nodes := g.sortedNodes()
+	index := make(map[string]int, len(nodes))
+	for idx, node := range nodes {
+		index[node.Name()] = idx
+	}
+	component := map[string]int{}
+	for idx, scc := range g.stronglyConnectedComponents() {
+		for _, node := range scc {
+			component[node.Name()] = idx
+		}
+	}
+
+	var cycles [][]*Module
+	for idx, start := range nodes {
+		finder := &cycleFinder{
+			graph:     g,
+			start:     start,
+			index:     index,
+			offset:    idx,
+			component: component,
+			blocked:   map[string]bool{},
+			blockedBy: map[string]map[string]bool{},
+		}
+		finder.search(start)
+		cycles = append(cycles, finder.cycles...)
+	}
+	g.logger.Debugf("Found %d dependency cycle(s).", len(cycles))
+	return cycles
+}
+
+// cycleFinder enumerates the elementary cycles that pass through a given start node without
+// traversing any node that sorts before it or that is not part of the same strongly connected
+// component. This guarantees that each cycle is only found once. Nodes from which the start node
+// can not be reached along the current path remain blocked until one of their successors becomes
+// part of a cycle, which avoids exploring the same dead ends repeatedly.
+type cycleFinder struct {
+	graph     *DepGraph
+	start     *Node
+	index     map[string]int
+	offset    int
+	component map[string]int
+	blocked   map[string]bool
+	blockedBy map[string]map[string]bool
+	path      []*Module
+	cycles    [][]*Module
+}
+
+// search returns whether a cycle through the start node was found that extends the current path
+// via the given node.
+func (f *cycleFinder) search(node *Node) bool {
+	found := false
+	f.path = append(f.path, node.Module)
+	f.blocked[node.Name()] = true
+
+	successors := f.successors(node)
+	for _, dep := range successors {
+		if dep.end == f.start.Name() {
+			cycle := make([]*Module, len(f.path))
+			copy(cycle, f.path)
+			f.cycles = append(f.cycles, cycle)
+			found = true
+		} else if !f.blocked[dep.end] && f.search(f.graph.nodes[dep.end]) {
+			found = true
+		}
+	}
+
+	if found {
+		f.unblock(node.Name())
+	} else {
+		for _, dep := range successors {
+			if f.blockedBy[dep.end] == nil {
+				f.blockedBy[dep.end] = map[string]bool{}
+			}
+			f.blockedBy[dep.end][node.Name()] = true
+		}
+	}
+	f.path = f.path[:len(f.path)-1]
+	return found
+}
+
+// successors returns the dependencies of the node that may be traversed by the search.
+func (f *cycleFinder) successors(node *Node) []*Dependency {
+	var successors []*Dependency
+	for _, dep := range node.sortedSuccessors() {
+		if f.index[dep.end] >= f.offset && f.component[dep.end] == f.component[f.start.Name()] {
+			successors = append(successors, dep)
+		}
+	}
+	return successors
+}
+
+func (f *cycleFinder) unblock(name string) {
+	f.blocked[name] = false
+	for other := range f.blockedBy[name] {
+		delete(f.blockedBy[name], other)
+		if f.blocked[other] {
+			f.unblock(other)
+		}
+	}
+}
+
+// stronglyConnectedComponents returns the strongly connected components of the DepGraph as computed
+// by Tarjan's algorithm. Components are returned in reverse topological order, meaning that each
+// component comes after all the components it depends on, and the nodes within a component are
+// ordered by path.
+func (g *DepGraph) stronglyConnectedComponents() [][]*Node {
+	finder := &sccFinder{
+		graph:   g,
+		index:   map[string]int{},
+		lowLink: map[string]int{},
+		onStack: map[string]bool{},
+	}
+	for _, node := range g.sortedNodes() {
+		if _, visited := finder.index[node.Name()]; !visited {
+			finder.visit(node)
+		}
+	}
+	return finder.components
+}
+
+type sccFinder struct {
+	graph      *DepGraph
+	index      map[string]int
+	lowLink    map[string]int
+	onStack    map[string]bool
+	stack      []*Node
+	components [][]*Node
+}
+
+func (f *sccFinder) visit(node *Node) {
+	name := node.Name()
+	f.index[name] = len(f.index)
+	f.lowLink[name] = f.index[name]
+	f.stack = append(f.stack, node)
+	f.onStack[name] = true
+
+	for _, dep := range node.sortedSuccessors() {
+		if _, visited := f.index[dep.end]; !visited {
+			f.visit(f.graph.nodes[dep.end])
+			if f.lowLink[dep.end] < f.lowLink[name] {
+				f.lowLink[name] = f.lowLink[dep.end]
+			}
+		} else if f.onStack[dep.end] && f.index[dep.end] < f.lowLink[name] {
+			f.lowLink[name] = f.index[dep.end]
+		}
+	}
+
+	if f.lowLink[name] != f.index[name] {
+		return
+	}
+	var component []*Node
+	for {
+		member := f.stack[len(f.stack)-1]
+		f.stack = f.stack[:len(f.stack)-1]
+		f.onStack[member.Name()] = false
+		component = append(component, member)
+		if member == node {
+			break
+		}
+	}
+	sort.Slice(component, func(i int, j int) bool { return component[i].Name() < component[j].Name() })
+	f.components = append(f.components, component)
+}
+
+// TopoSort returns all the modules of the DepGraph ordered such that each module comes after all the
+// modules it depends on. Among modules whose relative order is not constrained the one whose path
+// sorts first comes first. ErrCycle is returned if the DepGraph contains a dependency cycle.
+func (g *DepGraph) TopoSort() ([]*Module, error) {
+	nodes, acyclic := g.topoOrder()
+	if !acyclic {
+		var paths []string
+		for _, module := range g.anyCycle() {
+			paths = append(paths, module.Path)
+		}
+		g.logger.Errorf("Can not sort dependency graph due to the cycle %s.", strings.Join(paths, " -> "))
+		return nil, ErrCycle
+	}
+
+	sorted := make([]*Module, 0, len(nodes))
+	for _, node := range nodes {
+		sorted = append(sorted, node.Module)
+	}
+	return sorted, nil
+}
+
+// anyCycle returns one of the shortest cycles through the module whose path sorts first among the
+// ones that are part of a cycle, or nil if the DepGraph does not contain any cycle.
+func (g *DepGraph) anyCycle() []*Module {
+	var start *Node
+	for _, component := range g.stronglyConnectedComponents() {
+		if start != nil && component[0].Name() > start.Name() {
+			continue
+		}
+		if len(component) > 1 {
+			start = component[0]
+			continue
+		}
+		for _, dep := range component[0].successors {
+			if dep.end == component[0].Name() {
+				start = component[0]
+			}
+		}
+	}
+	if start == nil {
+		return nil
+	}
+
+	var cycle []*Module
+	for _, dep := range start.sortedSuccessors() {
+		path, err := g.ShortestPath(dep.end, start.Name())
+		if err == nil && (cycle == nil || len(path) < len(cycle)) {
+			cycle = path
+		}
+	}
+	return append([]*Module{start.Module}, cycle[:len(cycle)-1]...)
+}
+
+// topoOrder returns all the nodes of the DepGraph in the order described by TopoSort. When the
+// remaining nodes are all part of, or depend on, a cycle the one whose path sorts first is picked
+// next regardless of its unvisited dependencies so that each node is still returned exactly once.
+// Whether this never happened, meaning that the DepGraph does not contain any cycle, is returned as
+// well.
+func (g *DepGraph) topoOrder() ([]*Node, bool) {
+	remaining := make(map[string]int, len(g.nodes))
+	var ready []string
+	for name, node := range g.nodes {
+		remaining[name] = len(node.successors)
+		if len(node.successors) == 0 {
+			ready = append(ready, name)
+		}
+	}
+	sort.Strings(ready)
+
+	acyclic := true
+	allNodes := g.sortedNodes()
+	visited := make(map[string]bool, len(g.nodes))
+	sorted := make([]*Node, 0, len(g.nodes))
+	for len(sorted) < len(g.nodes) {
+		if len(ready) == 0 {
+			acyclic = false
+			for _, node := range allNodes {
+				if !visited[node.Name()] {
+					ready = append(ready, node.Name())
+					break
+				}
+			}
+		}
+		node := g.nodes[ready[0]]
+		ready = ready[1:]
+		visited[node.Name()] = true
+		sorted = append(sorted, node)
+		for _, dep := range node.predecessors {
+			remaining[dep.begin]--
+			if remaining[dep.begin] == 0 && !visited[dep.begin] {
+				idx := sort.SearchStrings(ready, dep.begin)
+				ready = append(ready[:idx], append([]string{dep.begin}, ready[idx:]...)...)
+			}
+		}
+	}
+	return sorted, acyclic
+}
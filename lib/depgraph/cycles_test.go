@@ -0,0 +1,117 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindCycles(t *testing.T) {
+	t.Run("NoCycles", func(t *testing.T) {
+		assert.Empty(t, testGraph().FindCycles(), "Should not find any cycles.")
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		graph := newTestGraph(
+			[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+			[][2]string{
+				{"test/module", "moduleA"},
+				{"test/module", "moduleB"},
+				{"moduleA", "moduleB"},
+				{"moduleB", "moduleC"},
+				{"moduleC", "moduleA"},
+				{"moduleC", "moduleD"},
+				{"moduleD", "moduleD"},
+				{"moduleD", "test/module"},
+			},
+		)
+		expected := [][]*Module{
+			{testModuleA, testModuleB, testModuleC},
+			{testModuleA, testModuleB, testModuleC, testModuleD, testMainModule},
+			{testModuleB, testModuleC, testModuleD, testMainModule},
+			{testModuleD},
+		}
+		assert.Equal(t, expected, graph.FindCycles(), "Should find all elementary cycles.")
+	})
+
+	t.Run("LargeAcyclicGraph", func(t *testing.T) {
+		assert.Empty(t, layeredTestGraph(100, 3).FindCycles(), "Should not find any cycles.")
+	})
+}
+
+func Test_StronglyConnectedComponents(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+			{"moduleD", "moduleC"},
+		},
+	)
+	var components [][]string
+	for _, component := range graph.stronglyConnectedComponents() {
+		var names []string
+		for _, node := range component {
+			names = append(names, node.Name())
+		}
+		components = append(components, names)
+	}
+	assert.Equal(t, [][]string{
+		{"moduleC", "moduleD"},
+		{"moduleA", "moduleB"},
+		{"test/module"},
+	}, components, "Should return components after the ones they depend on.")
+}
+
+func Benchmark_FindCycles(b *testing.B) {
+	graph := layeredTestGraph(100, 3)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		graph.FindCycles()
+	}
+}
+
+func Test_TopoSort(t *testing.T) {
+	sorted, err := testGraph().TopoSort()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleD, testModuleC, testModuleA, testModuleB, testMainModule}, sorted)
+
+	cyclic := newTestGraph(
+		[]*Module{testModuleA, testModuleB},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+		},
+	)
+	_, err = cyclic.TopoSort()
+	assert.Equal(t, ErrCycle, err)
+	assert.Equal(t, []*Module{testModuleA, testModuleB}, cyclic.anyCycle())
+
+	sorted, err = layeredTestGraph(100, 3).TopoSort()
+	assert.NoError(t, err, "Should sort large graphs without cycles.")
+	assert.Len(t, sorted, 301)
+	assert.Equal(t, "layer099/module0", sorted[0].Path)
+}
+
+func Test_AnyCycle(t *testing.T) {
+	assert.Nil(t, testGraph().anyCycle())
+
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleB"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+			{"moduleC", "moduleB"},
+			{"moduleD", "moduleB"},
+			{"moduleA", "moduleA"},
+		},
+	)
+	assert.Equal(t, []*Module{testModuleA}, graph.anyCycle(), "Should report self-dependencies.")
+	graph.removeEdge("moduleA", "moduleA")
+	assert.Equal(t, []*Module{testModuleB, testModuleC}, graph.anyCycle(), "Should report the shortest cycle.")
+}
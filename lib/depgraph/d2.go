@@ -0,0 +1,45 @@
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var d2Escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// WriteD2 writes the DepGraph as a D2 diagram to the specified writer. Each module is rendered as a
+// rectangle labelled with its path and, on a second line, its selected version. The main module is
+// rendered as a bold hexagon so that it stands out. All identifiers are quoted as module paths
+// contain characters that have a special meaning in D2.
+func (g *DepGraph) WriteD2(w io.Writer) error {
+	nodes := g.sortedNodes()
+
+	var lines []string
+	for _, node := range nodes {
+		label := d2Escaper.Replace(node.Name())
+		if version := node.SelectedVersion(); version != "" {
+			label += `\n` + d2Escaper.Replace(version)
+		}
+		style := "shape: rectangle"
+		if node.Name() == g.main.Name() {
+			style = "shape: hexagon; style.bold: true"
+		}
+		lines = append(lines, fmt.Sprintf("%s: \"%s\" {%s}", d2Quote(node.Name()), label, style))
+	}
+	for _, node := range nodes {
+		for _, dep := range node.sortedSuccessors() {
+			lines = append(lines, fmt.Sprintf("%s -> %s", d2Quote(dep.begin), d2Quote(dep.end)))
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		g.logger.WithError(err).Error("Failed to write D2 graph.")
+		return fmt.Errorf("could not write D2 graph: %v", err)
+	}
+	return nil
+}
+
+func d2Quote(identifier string) string {
+	return "\"" + d2Escaper.Replace(identifier) + "\""
+}
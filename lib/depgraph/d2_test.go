@@ -0,0 +1,30 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteD2(t *testing.T) {
+	const expectedOutput = `"moduleA": "moduleA\nv1.0.0" {shape: rectangle}
+"moduleB": "moduleB\nv1.1.0" {shape: rectangle}
+"moduleC": "moduleC\nv0.2.1" {shape: rectangle}
+"moduleD": "moduleD\nv2.0.0" {shape: rectangle}
+"test/module": "test/module" {shape: hexagon; style.bold: true}
+"moduleA" -> "moduleC"
+"moduleB" -> "moduleC"
+"moduleC" -> "moduleD"
+"test/module" -> "moduleA"
+"test/module" -> "moduleB"
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testGraph().WriteD2(writer), "Should not fail to write D2.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected D2 graph.")
+}
+
+func Test_D2Quote(t *testing.T) {
+	assert.Equal(t, `"github.com/foo/\"bar\"\\baz"`, d2Quote(`github.com/foo/"bar"\baz`))
+}
@@ -0,0 +1,55 @@
+package depgraph
+
+// GraphDiff describes the differences between two dependency graphs.
+type GraphDiff struct {
+	// Modules that are only present in the new graph.
+	Added []*Module
+	// Modules that are only present in the old graph.
+	Removed []*Module
+	// Modules present in both graphs but at different versions.
+	VersionChanges []ModuleChange
+	// Modules present in both graphs that gained, lost or changed a replace directive.
+	ReplaceChanges []ModuleChange
+}
+
+// ModuleChange contains the old and new information for a module that is present in both graphs
+// that are being compared.
+type ModuleChange struct {
+	Old *Module
+	New *Module
+}
+
+// Diff computes the differences between an old and a new dependency graph. All entries of the
+// resulting GraphDiff are ordered by module path.
+func Diff(oldGraph *DepGraph, newGraph *DepGraph) *GraphDiff {
+	oldGraph.logger.Debugf("Computing difference between dependency graphs of %q and %q.", oldGraph.Main().Name(), newGraph.Main().Name())
+
+	diff := &GraphDiff{}
+	for _, oldNode := range oldGraph.sortedNodes() {
+		newNode, ok := newGraph.nodes[oldNode.Name()]
+		if !ok {
+			diff.Removed = append(diff.Removed, oldNode.Module)
+			continue
+		}
+		change := ModuleChange{Old: oldNode.Module, New: newNode.Module}
+		if oldNode.Module.Version != newNode.Module.Version {
+			diff.VersionChanges = append(diff.VersionChanges, change)
+		}
+		if !sameReplace(oldNode.Module.Replace, newNode.Module.Replace) {
+			diff.ReplaceChanges = append(diff.ReplaceChanges, change)
+		}
+	}
+	for _, newNode := range newGraph.sortedNodes() {
+		if _, ok := oldGraph.nodes[newNode.Name()]; !ok {
+			diff.Added = append(diff.Added, newNode.Module)
+		}
+	}
+	return diff
+}
+
+func sameReplace(lhs *Module, rhs *Module) bool {
+	if lhs == nil || rhs == nil {
+		return lhs == rhs
+	}
+	return lhs.Path == rhs.Path && lhs.Version == rhs.Version
+}
@@ -0,0 +1,36 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	upgradedB := &Module{Path: "moduleB", Version: "v1.2.0"}
+	unreplacedC := &Module{Path: "moduleC", Version: "v0.2.0"}
+	replacedD := &Module{Path: "moduleD", Version: "v2.0.0", Replace: &Module{Path: "./moduleD"}}
+	moduleE := &Module{Path: "moduleE", Version: "v0.0.1"}
+
+	newGraph := newTestGraph(
+		[]*Module{upgradedB, unreplacedC, replacedD, moduleE},
+		[][2]string{
+			{"test/module", "moduleB"},
+			{"test/module", "moduleE"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+		},
+	)
+
+	assert.Equal(t, &GraphDiff{
+		Added:   []*Module{moduleE},
+		Removed: []*Module{testModuleA},
+		VersionChanges: []ModuleChange{
+			{Old: testModuleB, New: upgradedB},
+		},
+		ReplaceChanges: []ModuleChange{
+			{Old: testModuleC, New: unreplacedC},
+			{Old: testModuleD, New: replacedD},
+		},
+	}, Diff(testGraph(), newGraph))
+}
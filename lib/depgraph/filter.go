@@ -1,5 +1,9 @@
 package depgraph
 
+import (
+	"strings"
+)
+
 // PruneUnsharedDeps returns a copy of the dependency graph with all nodes removed
 // that are not part of a chain leading to a node with more than two predecessors.
 func (g *DepGraph) PruneUnsharedDeps() *DepGraph {
@@ -15,6 +19,265 @@ func (g *DepGraph) PruneUnsharedDeps() *DepGraph {
 	})
 }
 
+// DirectDependencies returns the modules which are directly required by the main module, ordered by
+// their path.
+func (g *DepGraph) DirectDependencies() []*Module {
+	var modules []*Module
+	for _, dep := range g.main.sortedSuccessors() {
+		modules = append(modules, g.nodes[dep.end].Module)
+	}
+	return modules
+}
+
+// PruneToDepth returns a copy of the dependency graph that only contains the modules which are at
+// most 'depth' dependency hops away from the main module. A depth of 0 results in a graph containing
+// only the main module.
+func (g *DepGraph) PruneToDepth(depth int) *DepGraph {
+	g.logger.Debugf("Pruning dependencies beyond a depth of %d.", depth)
+	depths := g.Depths()
+
+	prunedGraph := g.DeepCopy()
+	for name := range g.nodes {
+		if nodeDepth, ok := depths[name]; !ok || nodeDepth > depth {
+			g.logger.Debugf("Pruning %q.", name)
+			prunedGraph.removeNode(name)
+		}
+	}
+	return prunedGraph
+}
+
+// RootedSubGraph returns a copy of the dependency graph that has the specified module as its main
+// module and that only contains the modules which can be reached from it. The Main flag of the
+// modules in the copy is updated accordingly. ErrUnknownModule is
+// returned if the module is not part of the dependency graph.
+func (g *DepGraph) RootedSubGraph(root string) (*DepGraph, error) {
+	rootNode := g.Node(root)
+	if rootNode == nil {
+		g.logger.Errorf("Module %q is not part of the dependency graph.", root)
+		return nil, ErrUnknownModule
+	}
+
+	g.logger.Debugf("Extracting the dependency graph rooted at %q.", rootNode.Name())
+	subGraph := g.DeepCopy()
+	if rootNode != g.main {
+		// The modules are shared with the original graph so the ones whose flag changes are copied.
+		oldMain := *subGraph.main.Module
+		oldMain.Main = false
+		subGraph.main.Module = &oldMain
+		subGraph.main = subGraph.nodes[rootNode.Name()]
+		newMain := *subGraph.main.Module
+		newMain.Main = true
+		subGraph.main.Module = &newMain
+	}
+	depths := subGraph.Depths()
+	for name := range g.nodes {
+		if _, ok := depths[name]; !ok {
+			g.logger.Debugf("Pruning %q.", name)
+			subGraph.removeNode(name)
+		}
+	}
+	return subGraph, nil
+}
+
+// PathsToSubgraph returns a copy of the dependency graph that only contains the modules that are on
+// a path from the main module to at least one of the target modules. Targets that are not part of
+// the graph are skipped with a warning.
+func (g *DepGraph) PathsToSubgraph(targets []string) *DepGraph {
+	g.logger.Debugf("Retaining the paths leading to %v.", targets)
+
+	leadsToTarget := map[string]struct{}{}
+	var todo []*Node
+	for _, target := range targets {
+		node := g.Node(target)
+		if node == nil {
+			g.logger.Warnf("Module %q is not part of the dependency graph. Skipping it.", target)
+			continue
+		}
+		if _, ok := leadsToTarget[node.Name()]; !ok {
+			leadsToTarget[node.Name()] = struct{}{}
+			todo = append(todo, node)
+		}
+	}
+	for len(todo) > 0 {
+		for _, dep := range todo[0].predecessors {
+			if _, ok := leadsToTarget[dep.begin]; !ok {
+				leadsToTarget[dep.begin] = struct{}{}
+				todo = append(todo, g.nodes[dep.begin])
+			}
+		}
+		todo = todo[1:]
+	}
+
+	depths := g.Depths()
+	return g.retain(func(node *Node) bool {
+		_, reachable := depths[node.Name()]
+		_, leads := leadsToTarget[node.Name()]
+		return reachable && leads
+	})
+}
+
+// Filter returns a copy of the dependency graph that only contains the modules for which the keep
+// function returns true, as well as the main module which is always retained. Dependencies from or
+// to a module that is dropped are removed as well. No new dependencies are introduced to link the
+// remaining modules that were previously connected via a dropped module.
+func (g *DepGraph) Filter(keep func(*Module) bool) *DepGraph {
+	return g.retain(func(node *Node) bool { return keep(node.Module) })
+}
+
+// FilterByPrefix returns a copy of the dependency graph that only contains the modules whose path
+// starts with the given prefix as well as the dependencies between them. The main module is always
+// retained.
+func (g *DepGraph) FilterByPrefix(prefix string) *DepGraph {
+	g.logger.Debugf("Filtering out modules that do not match the prefix %q.", prefix)
+	return g.Filter(func(module *Module) bool {
+		return strings.HasPrefix(module.Path, prefix)
+	})
+}
+
+// CollapsePrefix returns a copy of the dependency graph in which all modules whose path starts with
+// the given prefix are merged into a single synthetic module whose path is the prefix itself. The
+// dependencies between merged modules are dropped while the ones from and to other modules are
+// rerouted to the synthetic module, with the weights of rerouted dependencies that end up between
+// the same modules being summed. The main module is never merged and, as the synthetic module would
+// collide with it, nothing is collapsed if the prefix is the main module's path.
+func (g *DepGraph) CollapsePrefix(prefix string) *DepGraph {
+	g.logger.Debugf("Collapsing modules with prefix %q.", prefix)
+	if prefix == g.main.Name() {
+		g.logger.Warnf("Not collapsing modules with prefix %q as it is the path of the main module.", prefix)
+		return g.DeepCopy()
+	}
+
+	collapsed := map[string]struct{}{}
+	for name := range g.nodes {
+		if name != g.main.Name() && strings.HasPrefix(name, prefix) {
+			collapsed[name] = struct{}{}
+		}
+	}
+	collapsedGraph := g.DeepCopy()
+	if len(collapsed) == 0 {
+		return collapsedGraph
+	}
+
+	incoming, outgoing := map[string]*Dependency{}, map[string]*Dependency{}
+	for _, node := range g.sortedNodes() {
+		if _, ok := collapsed[node.Name()]; !ok {
+			continue
+		}
+		for _, dep := range node.sortedSuccessors() {
+			if _, ok := collapsed[dep.end]; ok {
+				continue
+			}
+			if existing, ok := outgoing[dep.end]; ok {
+				existing.weight += dep.weight
+			} else {
+				outgoing[dep.end] = &Dependency{begin: prefix, end: dep.end, version: dep.version, weight: dep.weight}
+			}
+		}
+		for _, dep := range node.predecessors {
+			if _, ok := collapsed[dep.begin]; ok {
+				continue
+			}
+			if existing, ok := incoming[dep.begin]; ok {
+				existing.weight += dep.weight
+			} else {
+				incoming[dep.begin] = &Dependency{begin: dep.begin, end: prefix, version: dep.version, weight: dep.weight}
+			}
+		}
+	}
+
+	for name := range collapsed {
+		collapsedGraph.removeNode(name)
+	}
+	synthetic, _ := collapsedGraph.AddNode(&Module{Path: prefix})
+	for _, dep := range outgoing {
+		synthetic.successors = append(synthetic.successors, dep)
+		end := collapsedGraph.nodes[dep.end]
+		end.predecessors = append(end.predecessors, dep)
+	}
+	for _, dep := range incoming {
+		begin := collapsedGraph.nodes[dep.begin]
+		begin.successors = append(begin.successors, dep)
+		synthetic.predecessors = append(synthetic.predecessors, dep)
+	}
+	return collapsedGraph
+}
+
+// StripToolchainNodes returns a copy of the dependency graph without the 'go' and 'toolchain'
+// entries that recent Go versions report as dependencies in 'go mod graph', as well as without any
+// other synthetic module that lacks a version. The main module is always retained.
+func (g *DepGraph) StripToolchainNodes() *DepGraph {
+	g.logger.Debug("Removing toolchain entries from the dependency graph.")
+	return g.retain(func(node *Node) bool {
+		return node.Name() != "go" && node.Name() != "toolchain" && node.Module.Version != ""
+	})
+}
+
+// PruneTestOnly returns a copy of the dependency graph without the modules that are only required
+// by tests, as marked by AnnotateTestOnly.
+func (g *DepGraph) PruneTestOnly() *DepGraph {
+	g.logger.Debug("Removing test-only modules from the dependency graph.")
+	return g.retain(func(node *Node) bool { return !node.Module.TestOnly })
+}
+
+// retain returns a copy of the dependency graph from which all nodes have been removed for which
+// the keep function returns false, with the exception of the main module.
+func (g *DepGraph) retain(keep func(*Node) bool) *DepGraph {
+	filteredGraph := g.DeepCopy()
+	for name, node := range g.nodes {
+		if name != g.main.Name() && !keep(node) {
+			g.logger.Debugf("Filtering out %q.", name)
+			filteredGraph.removeNode(name)
+		}
+	}
+	return filteredGraph
+}
+
+// TransitiveReduction returns a copy of the dependency graph from which all dependencies have been
+// removed that are implied by other dependency chains. For example if module A requires both B and
+// C while B requires C as well, then the dependency of A on C is removed. Dependencies that are part
+// of a cycle are always retained.
+func (g *DepGraph) TransitiveReduction() *DepGraph {
+	g.logger.Debug("Computing the transitive reduction of the dependency graph.")
+	reducedGraph := g.DeepCopy()
+
+	// Edges are removed one at a time to guarantee that the reachability between modules is
+	// preserved, even when redundant edges imply each other via a cycle.
+	for _, edge := range reducedGraph.Edges() {
+		begin, end := edge.From.Path, edge.To.Path
+		if reducedGraph.reachable(end, begin, nil) {
+			continue
+		}
+		if reducedGraph.reachable(begin, end, &Dependency{begin: begin, end: end}) {
+			g.logger.Debugf("Removing redundant dependency of %q on %q.", begin, end)
+			reducedGraph.removeEdge(begin, end)
+		}
+	}
+	return reducedGraph
+}
+
+// reachable returns whether the 'to' node can be reached from the 'from' node. If 'skip' is not nil
+// then the corresponding dependency is not considered when evaluating reachability.
+func (g *DepGraph) reachable(from string, to string, skip *Dependency) bool {
+	visited := map[string]struct{}{from: {}}
+	todo := []string{from}
+	for len(todo) > 0 {
+		for _, dep := range g.nodes[todo[0]].successors {
+			if skip != nil && dep.begin == skip.begin && dep.end == skip.end {
+				continue
+			}
+			if dep.end == to {
+				return true
+			}
+			if _, ok := visited[dep.end]; !ok {
+				visited[dep.end] = struct{}{}
+				todo = append(todo, dep.end)
+			}
+		}
+		todo = todo[1:]
+	}
+	return false
+}
+
 // DependencyFilter allows to specify a dependency graph filter that removes any edges that
 // are not part of a chain leading to this dependency. If a version is given then we only keep
 // edges that prevent the use of the dependency at that given version due to the Go module's
@@ -0,0 +1,269 @@
+package depgraph
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func nodeNames(graph *DepGraph) []string {
+	var names []string
+	for name := range graph.Nodes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Test_DirectDependencies(t *testing.T) {
+	assert.Equal(t, []*Module{testModuleA, testModuleB}, testGraph().DirectDependencies())
+}
+
+func Test_SubGraph(t *testing.T) {
+	// Both 'moduleA' and 'moduleB' depend on 'moduleC' at the specified versions.
+	versionedGraph := func(versionA string, versionB string) *DepGraph {
+		graph := testGraph()
+		graph.Node("moduleA").successors[0].version = versionA
+		graph.Node("moduleB").successors[0].version = versionB
+		return graph
+	}
+
+	testcases := map[string]struct {
+		versionA string
+		versionB string
+		filter   string
+		expected []string
+	}{
+		// Requiring the target version itself does not prevent its use. This used to retain 'moduleA'
+		// as equal versions were considered to be more recent.
+		"EqualRelease": {
+			versionA: "v1.0.0",
+			versionB: "v0.9.0",
+			filter:   "v1.0.0",
+			expected: []string{"moduleC"},
+		},
+		// Regular pre-releases are ordered according to semantic versioning. This used to panic as only
+		// pseudo-versions could be compared with each other.
+		"PreRelease": {
+			versionA: "v1.0.0-rc.2",
+			versionB: "v1.0.0-rc.1",
+			filter:   "v1.0.0-rc.1",
+			expected: []string{"moduleA", "moduleC", "test/module"},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			graph := versionedGraph(tc.versionA, tc.versionB)
+			subGraph := graph.SubGraph([]*DependencyFilter{{Dependency: "moduleC", Version: tc.filter}})
+			assert.Equal(t, tc.expected, nodeNames(subGraph))
+		})
+	}
+}
+
+func Test_PruneToDepth(t *testing.T) {
+	testcases := map[string]struct {
+		depth    int
+		expected []string
+	}{
+		"MainOnly":    {depth: 0, expected: []string{"test/module"}},
+		"Direct":      {depth: 1, expected: []string{"moduleA", "moduleB", "test/module"}},
+		"Transitive":  {depth: 2, expected: []string{"moduleA", "moduleB", "moduleC", "test/module"}},
+		"FullDepth":   {depth: 3, expected: []string{"moduleA", "moduleB", "moduleC", "moduleD", "test/module"}},
+		"BeyondDepth": {depth: 10, expected: []string{"moduleA", "moduleB", "moduleC", "moduleD", "test/module"}},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			// The cycle between 'moduleA' and 'moduleD' should not affect the pruning.
+			graph := newTestGraph(
+				[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+				[][2]string{
+					{"test/module", "moduleA"},
+					{"test/module", "moduleB"},
+					{"moduleA", "moduleC"},
+					{"moduleB", "moduleC"},
+					{"moduleC", "moduleD"},
+					{"moduleD", "moduleA"},
+				},
+			)
+			pruned := graph.PruneToDepth(tc.depth)
+			assert.Equal(t, tc.expected, nodeNames(pruned), "Should have retained the expected nodes.")
+			assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+		})
+	}
+}
+
+func Test_RootedSubGraph(t *testing.T) {
+	graph := testGraph()
+
+	subGraph, err := graph.RootedSubGraph("moduleB")
+	assert.NoError(t, err)
+	assert.Equal(t, "moduleB", subGraph.Main().Name(), "Should use the root as main module.")
+	assert.True(t, subGraph.Main().Module.Main, "Should flag the root as main module.")
+	assert.False(t, testModuleB.Main, "Should not have modified the modules of the original graph.")
+	assert.Equal(t, []string{"moduleB", "moduleC", "moduleD"}, nodeNames(subGraph))
+	assert.Len(t, subGraph.Node("moduleC").Predecessors(), 1, "Should have dropped the edge from unreachable modules.")
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+
+	cyclic := newTestGraph(
+		[]*Module{testModuleA},
+		[][2]string{{"test/module", "moduleA"}, {"moduleA", "test/module"}},
+	)
+	subGraph, err = cyclic.RootedSubGraph("moduleA")
+	assert.NoError(t, err)
+	assert.False(t, subGraph.Node("test/module").Module.Main, "Should no longer flag the previous main module.")
+	assert.True(t, testMainModule.Main, "Should not have modified the modules of the original graph.")
+
+	subGraph, err = graph.RootedSubGraph("moduleC-fork")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"moduleC", "moduleD"}, nodeNames(subGraph), "Should resolve replaced modules.")
+
+	_, err = graph.RootedSubGraph("moduleE")
+	assert.Equal(t, ErrUnknownModule, err)
+}
+
+func Test_PathsToSubgraph(t *testing.T) {
+	graph := testGraph()
+
+	assert.Equal(t, []string{"moduleA", "test/module"}, nodeNames(graph.PathsToSubgraph([]string{"moduleA"})))
+	assert.Equal(
+		t,
+		[]string{"moduleA", "moduleB", "moduleC", "test/module"},
+		nodeNames(graph.PathsToSubgraph([]string{"moduleC-fork", "moduleE"})),
+		"Should resolve replaced targets and skip unknown ones.",
+	)
+	assert.Equal(t, []string{"test/module"}, nodeNames(graph.PathsToSubgraph(nil)))
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+
+	unreachable := newTestGraph(
+		[]*Module{testModuleA, testModuleB},
+		[][2]string{{"moduleB", "moduleA"}},
+	)
+	assert.Equal(t, []string{"test/module"}, nodeNames(unreachable.PathsToSubgraph([]string{"moduleA"})), "Should drop modules that are not reachable.")
+}
+
+func Test_Filter(t *testing.T) {
+	graph := testGraph()
+
+	filtered := graph.Filter(func(module *Module) bool { return module.Path != "moduleC" })
+	assert.Equal(t, []string{"moduleA", "moduleB", "moduleD", "test/module"}, nodeNames(filtered))
+	assert.Empty(t, filtered.Node("moduleA").Successors(), "Should have removed the edges to the dropped module.")
+	assert.Empty(t, filtered.Node("moduleD").Predecessors(), "Should not have added transitive edges.")
+
+	filtered = graph.Filter(func(*Module) bool { return false })
+	assert.Equal(t, []string{"test/module"}, nodeNames(filtered), "Should always keep the main module.")
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+}
+
+func Test_FilterByPrefix(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{
+			{Path: "github.com/org/foo"},
+			{Path: "github.com/org/bar"},
+			{Path: "github.com/other/baz"},
+		},
+		[][2]string{
+			{"test/module", "github.com/org/foo"},
+			{"test/module", "github.com/other/baz"},
+			{"github.com/other/baz", "github.com/org/bar"},
+			{"github.com/org/foo", "github.com/org/bar"},
+		},
+	)
+
+	filtered := graph.FilterByPrefix("github.com/org/")
+	assert.Equal(t, []string{"github.com/org/bar", "github.com/org/foo", "test/module"}, nodeNames(filtered))
+	assert.Len(t, filtered.Main().Successors(), 1, "Should have dropped the edge to the filtered out module.")
+	assert.Len(t, filtered.Node("github.com/org/bar").Predecessors(), 1, "Should have dropped the edge from the filtered out module.")
+}
+
+func Test_CollapsePrefix(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{
+			{Path: "k8s.io/api", Version: "v0.20.0"},
+			{Path: "k8s.io/client-go", Version: "v0.20.0"},
+			{Path: "github.com/foo", Version: "v1.0.0"},
+			{Path: "github.com/bar", Version: "v1.0.0"},
+		},
+		[][2]string{
+			{"test/module", "k8s.io/client-go"},
+			{"test/module", "github.com/foo"},
+			{"github.com/foo", "k8s.io/api"},
+			{"k8s.io/client-go", "k8s.io/api"},
+			{"k8s.io/client-go", "github.com/bar"},
+			{"k8s.io/api", "github.com/bar"},
+		},
+	)
+
+	collapsed := graph.CollapsePrefix("k8s.io/")
+	assert.Equal(t, []string{"github.com/bar", "github.com/foo", "k8s.io/", "test/module"}, nodeNames(collapsed))
+	synthetic := collapsed.Node("k8s.io/")
+	assert.Len(t, synthetic.Successors(), 1, "Should merge the rerouted dependencies on the same module.")
+	assert.Equal(t, "github.com/bar", synthetic.Successors()[0].End())
+	assert.Len(t, synthetic.Predecessors(), 2, "Should reroute the dependencies on merged modules.")
+	assert.Len(t, collapsed.Node("github.com/bar").Predecessors(), 1)
+	assert.Equal(t, "v1.0.0", synthetic.Successors()[0].RequiredVersion(), "Should retain the version of rerouted dependencies.")
+	for _, dep := range synthetic.Predecessors() {
+		assert.Equal(t, "v0.20.0", dep.RequiredVersion(), "Should retain the version of rerouted dependencies.")
+	}
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+
+	assert.Equal(t, nodeNames(graph), nodeNames(graph.CollapsePrefix("golang.org/")), "Should not change anything without a match.")
+
+	nested := newTestGraph([]*Module{{Path: "test/module/sub", Version: "v1.0.0"}}, [][2]string{{"test/module", "test/module/sub"}})
+	collapsed = nested.CollapsePrefix("test/module")
+	assert.Equal(t, []string{"test/module", "test/module/sub"}, nodeNames(collapsed), "Should not collapse into the main module.")
+	assert.Len(t, collapsed.Main().Successors(), 1)
+	assert.Empty(t, collapsed.Main().Predecessors(), "Should not add dependencies of the main module on itself.")
+}
+
+func Test_StripToolchainNodes(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{
+			testModuleA,
+			{Path: "go", Version: "1.21.0"},
+			{Path: "toolchain", Version: "go1.21.0"},
+			{Path: "synthetic"},
+		},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "go"},
+			{"test/module", "toolchain"},
+			{"moduleA", "go"},
+			{"moduleA", "synthetic"},
+		},
+	)
+
+	stripped := graph.StripToolchainNodes()
+	assert.Equal(t, []string{"moduleA", "test/module"}, nodeNames(stripped), "Should retain the main module.")
+	assert.Empty(t, stripped.Node("moduleA").Successors(), "Should have removed the edges to toolchain and synthetic nodes.")
+	assert.Len(t, stripped.Main().Successors(), 1, "Should have removed the edges from the main module.")
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+}
+
+func Test_TransitiveReduction(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"test/module", "moduleC"},
+			{"moduleA", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+			{"moduleD", "moduleC"},
+		},
+	)
+
+	reduced := graph.TransitiveReduction()
+	assert.Equal(t, []Edge{
+		{From: testModuleA, To: testModuleB, Version: "v1.1.0"},
+		{From: testModuleB, To: testModuleC, Version: "v0.2.0"},
+		{From: testModuleC, To: testModuleD, Version: "v2.0.0"},
+		{From: testModuleD, To: testModuleC, Version: "v0.2.0"},
+		{From: testMainModule, To: testModuleA, Version: "v1.0.0"},
+	}, reduced.Edges(), "Should have removed all redundant edges.")
+	assert.Len(t, graph.Edges(), 8, "Should not have modified the original graph.")
+}
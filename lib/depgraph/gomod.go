@@ -0,0 +1,120 @@
+package depgraph
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/modfile"
+)
+
+// BuildFromGoMods creates a DepGraph from the raw content of a set of go.mod files, keyed by the
+// path of the module to which they belong, without accessing the filesystem or running any 'go'
+// commands. Starting from the 'main' module the require directives of each go.mod file are followed
+// and the highest required version of each module is selected. Modules without a go.mod file in the
+// set have no dependencies. As with the 'go' tool only the replace directives of the main module's
+// go.mod are applied. The requirements of a module that is replaced by another module, for all of
+// its versions, are taken from the go.mod of the override if it is part of the set. The 'logger'
+// parameter can be 'nil' which will result in no output or logging information to be provided.
+func BuildFromGoMods(logger *logrus.Logger, main string, mods map[string]string) (*DepGraph, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+	logger.Debugf("Creating dependency graph for %q from %d go.mod files.", main, len(mods))
+
+	if _, ok := mods[main]; !ok {
+		return nil, fmt.Errorf("no go.mod content provided for main module %q", main)
+	}
+
+	mainGoMod, err := parseGoMod(main, mods[main])
+	if err != nil {
+		return nil, err
+	}
+	// The go.mod files of modules that are replaced by another module are those of the override.
+	goModOf := map[string]string{}
+	for _, replace := range mainGoMod.Replaces {
+		if _, ok := mods[replace.Override]; ok && replace.OriginalVersion == "" && replace.Original != main {
+			goModOf[replace.Original] = replace.Override
+		}
+	}
+
+	type requirement struct {
+		begin string
+		modfile.Require
+	}
+	var requirements []requirement
+	todo := []string{main}
+	visited := map[string]bool{main: true}
+	for len(todo) > 0 {
+		current := todo[0]
+		todo = todo[1:]
+		goMod := mainGoMod
+		if current != main {
+			source := current
+			if override, replaced := goModOf[current]; replaced {
+				logger.Debugf("Using the go.mod of %q for replaced module %q.", override, current)
+				source = override
+			}
+			content, ok := mods[source]
+			if !ok {
+				continue
+			}
+			if goMod, err = parseGoMod(source, content); err != nil {
+				return nil, err
+			}
+		}
+		for _, require := range goMod.Requires {
+			requirements = append(requirements, requirement{begin: current, Require: require})
+			if !visited[require.Path] {
+				visited[require.Path] = true
+				todo = append(todo, require.Path)
+			}
+		}
+	}
+
+	modules := map[string]*Module{main: {Main: true, Path: main}}
+	for _, require := range requirements {
+		if module, ok := modules[require.Path]; !ok {
+			modules[require.Path] = &Module{Path: require.Path, Version: require.Version}
+		} else if !module.Main && moduleMoreRecentThan(require.Version, module.Version) {
+			module.Version = require.Version
+		}
+		if require.begin == main && require.Indirect {
+			modules[require.Path].Indirect = true
+		}
+	}
+	for _, replace := range mainGoMod.Replaces {
+		module, ok := modules[replace.Original]
+		if !ok || module.Main || (replace.OriginalVersion != "" && replace.OriginalVersion != module.Version) {
+			continue
+		}
+		module.Replace = &Module{Path: replace.Override, Version: replace.Version}
+	}
+
+	graph := NewGraph(logger, modules[main])
+	for _, require := range requirements {
+		err := graph.addDependency(&rawDependency{
+			begineNodeName: require.begin,
+			beginVersion:   modules[require.begin].Version,
+			beginModule:    modules[require.begin],
+			endNodeName:    require.Path,
+			endVersion:     require.Version,
+			endModule:      modules[require.Path],
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+// parseGoMod parses the content of the go.mod file of the specified module.
+func parseGoMod(module string, content string) (*modfile.File, error) {
+	goMod, err := modfile.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid go.mod of %q: %v", module, err)
+	}
+	return goMod, nil
+}
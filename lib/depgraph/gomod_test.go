@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildFromGoMods(t *testing.T) {
+	mods := map[string]string{
+		"test/module": `module test/module
+
+go 1.12
+
+require (
+	moduleA v1.0.0
+	moduleB v1.1.0
+	moduleD v2.0.0 // indirect
+)
+
+replace moduleC => moduleC-fork v0.2.1 // Upstream is broken.
+`,
+		"moduleA": `module moduleA
+
+require moduleC v0.1.0
+`,
+		"moduleB": `module moduleB
+
+require (
+	moduleC v0.2.0
+)
+`,
+		"moduleC": `module moduleC
+
+require moduleD v1.8.0
+`,
+		"moduleC-fork": `module moduleC-fork
+
+require moduleD v1.9.0
+`,
+		"moduleE": `module moduleE
+
+require moduleA v1.2.0
+`,
+	}
+
+	graph, err := BuildFromGoMods(nil, "test/module", mods)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"moduleA", "moduleB", "moduleC", "moduleD", "test/module"}, nodeNames(graph), "Should only contain reachable modules.")
+	assert.True(t, graph.Main().Module.Main)
+
+	moduleC := graph.Node("moduleC-fork")
+	require.NotNil(t, moduleC, "Should apply the main module's replaces.")
+	assert.Equal(t, "v0.2.0", moduleC.Module.Version, "Should select the highest required version.")
+	assert.Equal(t, "v0.2.1", moduleC.SelectedVersion())
+	assert.Equal(t, []string{"v0.1.0", "v0.2.0"}, graph.VersionConflicts()["moduleC"])
+
+	assert.Equal(t, "v2.0.0", graph.Node("moduleD").Module.Version)
+	assert.True(t, graph.Node("moduleD").Module.Indirect, "Should mark indirect requirements of the main module.")
+	assert.False(t, graph.Node("moduleA").Module.Indirect)
+	assert.Len(t, graph.Edges(), 6)
+	if assert.Len(t, moduleC.Successors(), 1) {
+		assert.Equal(t, "v1.9.0", moduleC.Successors()[0].RequiredVersion(), "Should use the go.mod of the override.")
+	}
+
+	_, err = BuildFromGoMods(nil, "unknown/module", mods)
+	assert.Error(t, err, "Should require the main module's go.mod.")
+
+	_, err = BuildFromGoMods(nil, "moduleA", map[string]string{"moduleA": "require moduleB"})
+	assert.Error(t, err, "Should reject invalid require directives.")
+
+	graph, err = BuildFromGoMods(nil, "moduleA", map[string]string{
+		"moduleA": "\ufeffmodule moduleA\r\n\r\nrequire moduleB v1.0.0 // Comment with moduleC v1.0.0.\r\n",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"moduleA", "moduleB"}, nodeNames(graph), "Should normalize the content and ignore comments.")
+}
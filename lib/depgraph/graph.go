@@ -1,33 +1,50 @@
 package depgraph
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io/ioutil"
 	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/util"
 )
 
 type Module struct {
-	Main    bool         // is this the main module?
-	Path    string       // module path
-	Replace *Module      // replaced by this module
-	Version string       // module version
-	Time    *time.Time   // time version was created
-	Update  *Module      // available update, if any (with -u)
-	GoMod   string       // the path to this module's go.mod file
-	Error   *ModuleError // error loading module
+	Main     bool         // is this the main module?
+	Path     string       // module path
+	Replace  *Module      // replaced by this module
+	Version  string       // module version
+	Time     *time.Time   // time version was created
+	Update   *Module      // available update, if any (with -u)
+	Indirect bool         // is this module only an indirect dependency of main module?
+	TestOnly bool         // is this module only required by tests? (with AnnotateTestOnly)
+	GoMod    string       // the path to this module's go.mod file
+	Error    *ModuleError // error loading module
 }
 
 type ModuleError struct {
 	Err string // the error itself
 }
 
+// IsLocalReplace returns whether the module is replaced by a directory on the local filesystem
+// rather than by another module. Paths are recognised in the same way as for the overrides of
+// replace directives, i.e. they are absolute or start with './' or '../'.
+func (m *Module) IsLocalReplace() bool {
+	return m.Replace != nil && util.IsLocalPath(m.Replace.Path)
+}
+
 // DepGraph represents a Go module's dependency graph.
 type DepGraph struct {
 	logger   *logrus.Logger
 	main     *Node
 	nodes    map[string]*Node
 	replaces map[string]string
+	// Modules that were encountered at several versions while building the DepGraph but were
+	// merged into a single node as nodes are indexed by module path.
+	duplicates map[string][]*Module
 }
 
 // NewGraph returns a new DepGraph instance which will use the specified
@@ -38,19 +55,28 @@ func NewGraph(logger *logrus.Logger, main *Module) *DepGraph {
 		logger.SetOutput(ioutil.Discard)
 	}
 	newGraph := &DepGraph{
-		logger:   logger,
-		nodes:    map[string]*Node{},
-		replaces: map[string]string{},
+		logger:     logger,
+		nodes:      map[string]*Node{},
+		replaces:   map[string]string{},
+		duplicates: map[string][]*Module{},
 	}
 	mainNode, _ := newGraph.AddNode(main)
 	newGraph.main = mainNode
 	return newGraph
 }
 
+// Main returns the node of the DepGraph's main module. It can be used to anchor traversals of the
+// graph.
 func (g *DepGraph) Main() *Node {
 	return g.main
 }
 
+// MainModule returns the DepGraph's main module. It is a shorthand for the Module of the node
+// returned by Main.
+func (g *DepGraph) MainModule() *Module {
+	return g.main.Module
+}
+
 func (g *DepGraph) Node(name string) *Node {
 	if replaced, ok := g.replaces[name]; ok {
 		name = replaced
@@ -58,15 +84,147 @@ func (g *DepGraph) Node(name string) *Node {
 	return g.nodes[name]
 }
 
+// SelectedVersion returns the version of the module with the given path that is used in the build,
+// taking into account any replacement. The second return value indicates whether the module is part
+// of the DepGraph.
+func (g *DepGraph) SelectedVersion(path string) (string, bool) {
+	node := g.Node(path)
+	if node == nil {
+		return "", false
+	}
+	return node.SelectedVersion(), true
+}
+
+// DuplicatePaths returns the module paths that were encountered at more than one version while
+// building the DepGraph, together with the modules at each of these versions in the order in which
+// they were encountered. As nodes are indexed by module path only one of these modules is part of
+// the DepGraph. This happens when the module list reported by 'go list -m all' contains a
+// path several times or when AddNode is called for a path that is already present at another
+// version. A graph that results from a proper minimal version selection yields an empty map. Note
+// that ParseModGraph selects the highest version of each path and never reports duplicates.
+func (g *DepGraph) DuplicatePaths() map[string][]*Module {
+	duplicates := map[string][]*Module{}
+	for path, modules := range g.duplicates {
+		duplicates[path] = append([]*Module(nil), modules...)
+	}
+	return duplicates
+}
+
+// recordDuplicate registers that the module at the path of 'existing' was also encountered at the
+// version of 'module'.
+func (g *DepGraph) recordDuplicate(existing *Module, module *Module) {
+	if len(g.duplicates[existing.Path]) == 0 {
+		g.duplicates[existing.Path] = []*Module{existing}
+	}
+	for _, known := range g.duplicates[existing.Path] {
+		if known.Version == module.Version {
+			return
+		}
+	}
+	g.logger.Warnf("Module %q is present at both version %q and %q.", existing.Path, existing.Version, module.Version)
+	g.duplicates[existing.Path] = append(g.duplicates[existing.Path], module)
+}
+
 func (g *DepGraph) Nodes() map[string]*Node {
 	return g.nodes
 }
 
+// Hash returns the hex-encoded SHA-256 hash of a canonical representation of the DepGraph. It
+// covers the path and version of each module, their replacements and the dependencies between them
+// with their required version. As everything is sorted beforehand the hash only changes if any of
+// these does, which allows to detect whether a graph changed without comparing it in full.
+func (g *DepGraph) Hash() string {
+	hash := sha256.New()
+	for _, node := range g.sortedNodes() {
+		module := node.Module
+		fmt.Fprintf(hash, "module\t%s\t%s\t%t\n", module.Path, module.Version, module.Main)
+		if module.Replace != nil {
+			fmt.Fprintf(hash, "replace\t%s\t%s\n", module.Replace.Path, module.Replace.Version)
+		}
+	}
+	for _, node := range g.sortedNodes() {
+		for _, dep := range node.sortedSuccessors() {
+			fmt.Fprintf(hash, "require\t%s\t%s\t%s\n", dep.begin, dep.end, dep.version)
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Edge represents a dependency of one module on another.
+type Edge struct {
+	From *Module
+	To   *Module
+	// Version of To that is required by From. It can be lower than the selected version of To if
+	// another module requires a higher one.
+	Version string
+	// Number of packages of From that import a package of To. Only set if the graph has been
+	// annotated via AnnotateWeights.
+	Weight int
+}
+
+// Edges returns all the dependencies in the DepGraph, ordered by the path of the depending module
+// and subsequently by the path of the required module.
+func (g *DepGraph) Edges() []Edge {
+	var edges []Edge
+	for _, node := range g.sortedNodes() {
+		for _, dep := range node.sortedSuccessors() {
+			edges = append(edges, Edge{
+				From:    node.Module,
+				To:      g.nodes[dep.end].Module,
+				Version: dep.version,
+				Weight:  dep.weight,
+			})
+		}
+	}
+	return edges
+}
+
+// AdjacencyList returns the path of each module in the DepGraph mapped to the sorted paths of the
+// modules it directly depends on. Modules without dependencies are mapped to an empty list. This
+// allows to feed the graph's structure into other graph libraries.
+func (g *DepGraph) AdjacencyList() map[string][]string {
+	adjacency := make(map[string][]string, len(g.nodes))
+	for name, node := range g.nodes {
+		deps := make([]string, 0, len(node.successors))
+		for _, dep := range node.sortedSuccessors() {
+			deps = append(deps, dep.end)
+		}
+		adjacency[name] = deps
+	}
+	return adjacency
+}
+
+// Leaves returns the modules that do not depend on any other module, ordered by path.
+func (g *DepGraph) Leaves() []*Module {
+	var leaves []*Module
+	for _, node := range g.sortedNodes() {
+		if len(node.successors) == 0 {
+			leaves = append(leaves, node.Module)
+		}
+	}
+	return leaves
+}
+
+// Roots returns the modules, other than the main module, that are not required by any other
+// module, ordered by path.
+func (g *DepGraph) Roots() []*Module {
+	var roots []*Module
+	for _, node := range g.sortedNodes() {
+		if len(node.predecessors) == 0 && node != g.main {
+			roots = append(roots, node.Module)
+		}
+	}
+	return roots
+}
+
 func (g *DepGraph) AddNode(module *Module) (*Node, bool) {
 	if module == nil {
 		return nil, false
 	}
 	if node, ok := g.nodes[module.Path]; ok && node != nil {
+		if node.Module.Version != module.Version {
+			g.recordDuplicate(node.Module, module)
+		}
 		return node, true
 	}
 	newNode := &Node{Module: module}
@@ -77,6 +235,43 @@ func (g *DepGraph) AddNode(module *Module) (*Node, bool) {
 	return newNode, true
 }
 
+// AddEdge records a dependency of the module with path 'fromPath' on the one with path 'toPath' at
+// the latter's version. Both modules must already be part of the DepGraph, otherwise
+// ErrUnknownModule is returned. Adding a dependency that already exists has no effect.
+func (g *DepGraph) AddEdge(fromPath string, toPath string) error {
+	begin, end := g.Node(fromPath), g.Node(toPath)
+	if begin == nil || end == nil {
+		g.logger.Errorf("Can not add dependency of %q on %q as one of them is not part of the dependency graph.", fromPath, toPath)
+		return ErrUnknownModule
+	}
+	for _, dep := range begin.successors {
+		if dep.end == end.Name() {
+			return nil
+		}
+	}
+
+	dependency := &Dependency{begin: begin.Name(), end: end.Name(), version: end.Module.Version}
+	begin.successors = append(begin.successors, dependency)
+	end.predecessors = append(end.predecessors, dependency)
+	return nil
+}
+
+// RemoveNode removes the module with the given path from the DepGraph together with all the
+// dependencies from and to it. It returns whether the module was removed. The main module can not
+// be removed.
+func (g *DepGraph) RemoveNode(path string) bool {
+	node := g.Node(path)
+	if node == nil {
+		return false
+	}
+	if node == g.main {
+		g.logger.Warnf("Refusing to remove the main module %q from the dependency graph.", node.Name())
+		return false
+	}
+	g.removeNode(node.Name())
+	return true
+}
+
 // Node represents a module in a Go module's dependency graph.
 type Node struct {
 	Module       *Module
@@ -132,6 +327,7 @@ type Dependency struct {
 	begin   string
 	end     string
 	version string
+	weight  int
 }
 
 // Begin returns the name of the Go module at which this Dependency originates.
@@ -150,6 +346,12 @@ func (d *Dependency) RequiredVersion() string {
 	return d.version
 }
 
+// Weight is the number of packages of the depending Go module that import a package of the
+// required Go module. It is only known if the graph has been annotated via AnnotateWeights.
+func (d *Dependency) Weight() int {
+	return d.weight
+}
+
 // DeepCopy returns a separate copy of the current dependency graph that can be
 // safely modified without affecting the original graph. The logger argument can
 // be nil in which case nothing will be logged.
@@ -172,6 +374,9 @@ func (g *DepGraph) DeepCopy() *DepGraph {
 			endNode.predecessors = append(endNode.predecessors, &dependencyCopy)
 		}
 	}
+	for path, modules := range g.duplicates {
+		newGraph.duplicates[path] = append([]*Module(nil), modules...)
+	}
 	g.logger.Debug("Created a deep copy of graph.")
 	return newGraph
 }
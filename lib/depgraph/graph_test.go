@@ -0,0 +1,224 @@
+package depgraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testMainModule = &Module{Main: true, Path: "test/module"}
+	testModuleA    = &Module{Path: "moduleA", Version: "v1.0.0"}
+	testModuleB    = &Module{Path: "moduleB", Version: "v1.1.0"}
+	testModuleC    = &Module{Path: "moduleC", Version: "v0.2.0", Replace: &Module{Path: "moduleC-fork", Version: "v0.2.1"}}
+	testModuleD    = &Module{Path: "moduleD", Version: "v2.0.0"}
+)
+
+// newTestGraph returns a graph with the test main module and the specified edges. Each edge is
+// given as a pair of module paths and requires the selected version of the module it points to.
+func newTestGraph(modules []*Module, edges [][2]string) *DepGraph {
+	graph := NewGraph(nil, testMainModule)
+	for _, module := range modules {
+		graph.AddNode(module)
+	}
+	for _, edge := range edges {
+		begin, end := graph.Node(edge[0]), graph.Node(edge[1])
+		dependency := &Dependency{begin: begin.Name(), end: end.Name(), version: end.Module.Version}
+		begin.successors = append(begin.successors, dependency)
+		end.predecessors = append(end.predecessors, dependency)
+	}
+	return graph
+}
+
+// testGraph returns a small graph with a diamond dependency on 'moduleC':
+//
+//	test/module -> moduleA -> moduleC
+//	test/module -> moduleB -> moduleC -> moduleD
+func testGraph() *DepGraph {
+	return newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+		},
+	)
+}
+
+// layeredTestGraph returns a graph without cycles in which the main module depends on all the
+// modules of the first of 'layers' layers of 'width' modules each, and each module depends on all
+// the modules of the next layer. The number of paths through the graph grows exponentially with
+// the number of layers.
+func layeredTestGraph(layers int, width int) *DepGraph {
+	var modules []*Module
+	var edges [][2]string
+	previous := []string{"test/module"}
+	for layer := 0; layer < layers; layer++ {
+		var current []string
+		for idx := 0; idx < width; idx++ {
+			module := &Module{Path: fmt.Sprintf("layer%03d/module%d", layer, idx), Version: "v1.0.0"}
+			modules = append(modules, module)
+			current = append(current, module.Path)
+			for _, dependent := range previous {
+				edges = append(edges, [2]string{dependent, module.Path})
+			}
+		}
+		previous = current
+	}
+	return newTestGraph(modules, edges)
+}
+
+func Test_IsLocalReplace(t *testing.T) {
+	assert.False(t, testModuleA.IsLocalReplace(), "Should not report modules that are not replaced.")
+	assert.False(t, testModuleC.IsLocalReplace(), "Should not report modules replaced by another module.")
+	for _, path := range []string{"./moduleC", "../moduleC", "/tmp/moduleC"} {
+		module := &Module{Path: "moduleC", Version: "v0.2.0", Replace: &Module{Path: path}}
+		assert.True(t, module.IsLocalReplace(), "Should report a replacement by %q.", path)
+	}
+}
+
+func Test_DeepCopy(t *testing.T) {
+	original := testGraph()
+	copied := original.DeepCopy()
+	assert.Len(t, copied.Nodes(), len(original.Nodes()), "Should have copied all nodes.")
+	for name, node := range original.Nodes() {
+		copiedNode := copied.Node(name)
+		assert.NotNil(t, copiedNode, "Should have copied node %q.", name)
+		assert.Equal(t, node.Module, copiedNode.Module, "Should have copied module information for %q.", name)
+		assert.ElementsMatch(t, node.Successors(), copiedNode.Successors(), "Should have copied successors of %q.", name)
+		assert.ElementsMatch(t, node.Predecessors(), copiedNode.Predecessors(), "Should have copied predecessors of %q.", name)
+	}
+
+	copied.removeNode("moduleC")
+	assert.NotNil(t, original.Node("moduleC"), "Should not have modified the original graph.")
+	assert.Len(t, original.Node("moduleA").Successors(), 1, "Should not have modified the original graph's edges.")
+}
+
+func Test_Main(t *testing.T) {
+	graph := testGraph()
+	assert.Equal(t, "test/module", graph.Main().Name())
+	assert.Equal(t, testMainModule, graph.MainModule())
+}
+
+func Test_DuplicatePaths(t *testing.T) {
+	assert.Empty(t, testGraph().DuplicatePaths(), "Should not report duplicates for a well-formed graph.")
+
+	graph := testGraph()
+	olderC := &Module{Path: "moduleC", Version: "v0.1.0"}
+	node, ok := graph.AddNode(olderC)
+	assert.True(t, ok)
+	assert.Equal(t, testModuleC, node.Module, "Should retain the existing node.")
+	graph.AddNode(&Module{Path: "moduleC", Version: "v0.1.0"})
+	graph.AddNode(testModuleC)
+	duplicates := map[string][]*Module{"moduleC": {testModuleC, olderC}}
+	assert.Equal(t, duplicates, graph.DuplicatePaths(), "Should report each version of a module once.")
+	assert.Equal(t, duplicates, graph.DeepCopy().DuplicatePaths(), "Should retain duplicates when copying.")
+}
+
+func Test_Hash(t *testing.T) {
+	hash := testGraph().Hash()
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, testGraph().Hash(), "Should be reproducible.")
+	assert.Equal(t, hash, testGraph().DeepCopy().Hash(), "Should not depend on the order of nodes and edges.")
+
+	assert.NotEqual(t, hash, versionedTestGraph().Hash(), "Should change with required versions.")
+	pruned := testGraph()
+	pruned.removeEdge("moduleC", "moduleD")
+	assert.NotEqual(t, hash, pruned.Hash(), "Should change with dependencies.")
+	replaced := newTestGraph(
+		[]*Module{testModuleA, testModuleB, {Path: "moduleC", Version: "v0.2.0"}, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+		},
+	)
+	assert.NotEqual(t, hash, replaced.Hash(), "Should change with replacements.")
+}
+
+func Test_Edges(t *testing.T) {
+	assert.Equal(t, []Edge{
+		{From: testModuleA, To: testModuleC, Version: "v0.2.0"},
+		{From: testModuleB, To: testModuleC, Version: "v0.2.0"},
+		{From: testModuleC, To: testModuleD, Version: "v2.0.0"},
+		{From: testMainModule, To: testModuleA, Version: "v1.0.0"},
+		{From: testMainModule, To: testModuleB, Version: "v1.1.0"},
+	}, testGraph().Edges())
+
+	upgraded := versionedTestGraph().Edges()[0]
+	assert.Equal(t, testModuleC, upgraded.To)
+	assert.Equal(t, "v0.1.0", upgraded.Version, "Should report the required rather than the selected version.")
+}
+
+func Test_AdjacencyList(t *testing.T) {
+	assert.Equal(t, map[string][]string{
+		"test/module": {"moduleA", "moduleB"},
+		"moduleA":     {"moduleC"},
+		"moduleB":     {"moduleC"},
+		"moduleC":     {"moduleD"},
+		"moduleD":     {},
+	}, testGraph().AdjacencyList())
+}
+
+func Test_LeavesAndRoots(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleB", "moduleC"},
+			{"moduleB", "moduleD"},
+		},
+	)
+	assert.Equal(t, []*Module{testModuleA, testModuleC, testModuleD}, graph.Leaves(), "Should return the expected leaves.")
+	assert.Equal(t, []*Module{testModuleB}, graph.Roots(), "Should return the expected roots.")
+}
+
+func Test_AddEdge(t *testing.T) {
+	graph := NewGraph(nil, testMainModule)
+	graph.AddNode(testModuleA)
+	graph.AddNode(testModuleC)
+
+	assert.NoError(t, graph.AddEdge("test/module", "moduleA"))
+	assert.NoError(t, graph.AddEdge("moduleA", "moduleC-fork"), "Should resolve replaced modules.")
+	assert.NoError(t, graph.AddEdge("moduleA", "moduleC"), "Should ignore duplicate edges.")
+	assert.Equal(t, []Edge{
+		{From: testModuleA, To: testModuleC, Version: "v0.2.0"},
+		{From: testMainModule, To: testModuleA, Version: "v1.0.0"},
+	}, graph.Edges())
+	assert.Equal(t, "v0.2.0", graph.Node("moduleA").Successors()[0].RequiredVersion())
+
+	assert.Equal(t, ErrUnknownModule, graph.AddEdge("moduleA", "moduleE"))
+	assert.Equal(t, ErrUnknownModule, graph.AddEdge("moduleE", "moduleA"))
+}
+
+func Test_RemoveNode(t *testing.T) {
+	graph := testGraph()
+	assert.False(t, graph.RemoveNode("test/module"), "Should refuse to remove the main module.")
+	assert.False(t, graph.RemoveNode("moduleE"), "Should not remove an unknown module.")
+	assert.Len(t, graph.Nodes(), 5, "Should not have removed any module.")
+
+	assert.True(t, graph.RemoveNode("moduleC-fork"), "Should remove a module via its replacement.")
+	assert.Nil(t, graph.Node("moduleC"), "Should have removed the module.")
+	assert.Empty(t, graph.Node("moduleA").Successors(), "Should have removed incoming edges.")
+	assert.Empty(t, graph.Node("moduleD").Predecessors(), "Should have removed outgoing edges.")
+}
+
+func Test_SelectedVersion(t *testing.T) {
+	graph := testGraph()
+
+	version, ok := graph.SelectedVersion("moduleA")
+	assert.True(t, ok)
+	assert.Equal(t, "v1.0.0", version)
+
+	version, ok = graph.SelectedVersion("moduleC")
+	assert.True(t, ok)
+	assert.Equal(t, "v0.2.1", version, "Should return the version of the replacement.")
+
+	_, ok = graph.SelectedVersion("moduleE")
+	assert.False(t, ok, "Should report unknown modules.")
+}
@@ -0,0 +1,92 @@
+package depgraph
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const graphMLNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the DepGraph in GraphML format to the specified writer so that it can be
+// imported in tools such as Gephi or yEd. Each node carries the 'path' and 'version' of the module
+// it represents.
+func (g *DepGraph) WriteGraphML(w io.Writer) error {
+	output := graphML{
+		XMLNS: graphMLNamespace,
+		Keys: []graphMLKey{
+			{ID: "path", For: "node", AttrName: "path", AttrType: "string"},
+			{ID: "version", For: "node", AttrName: "version", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: g.main.Name(), EdgeDefault: "directed"},
+	}
+
+	nodes := g.sortedNodes()
+	ids := make(map[string]string, len(nodes))
+	for idx, node := range nodes {
+		ids[node.Name()] = fmt.Sprintf("n%d", idx)
+		output.Graph.Nodes = append(output.Graph.Nodes, graphMLNode{
+			ID: ids[node.Name()],
+			Data: []graphMLData{
+				{Key: "path", Value: node.Name()},
+				{Key: "version", Value: node.SelectedVersion()},
+			},
+		})
+	}
+	for _, node := range nodes {
+		for _, dep := range node.sortedSuccessors() {
+			output.Graph.Edges = append(output.Graph.Edges, graphMLEdge{
+				ID:     fmt.Sprintf("e%d", len(output.Graph.Edges)),
+				Source: ids[dep.begin],
+				Target: ids[dep.end],
+			})
+		}
+	}
+
+	raw, err := xml.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not generate GraphML: %v", err)
+	}
+	if _, err = io.WriteString(w, xml.Header+string(raw)+"\n"); err != nil {
+		g.logger.WithError(err).Error("Failed to write GraphML graph.")
+		return fmt.Errorf("could not write GraphML graph: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteGraphML(t *testing.T) {
+	graph := newTestGraph([]*Module{testModuleC}, [][2]string{{"test/module", "moduleC"}})
+
+	const expectedOutput = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="path" for="node" attr.name="path" attr.type="string"></key>
+  <key id="version" for="node" attr.name="version" attr.type="string"></key>
+  <graph id="test/module" edgedefault="directed">
+    <node id="n0">
+      <data key="path">moduleC</data>
+      <data key="version">v0.2.1</data>
+    </node>
+    <node id="n1">
+      <data key="path">test/module</data>
+      <data key="version"></data>
+    </node>
+    <edge id="e0" source="n1" target="n0"></edge>
+  </graph>
+</graphml>
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, graph.WriteGraphML(writer), "Should not fail to write GraphML.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected GraphML.")
+}
@@ -0,0 +1,133 @@
+package depgraph
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// htmlTemplate renders a self-contained page that displays a DepGraph as a tree of collapsible
+// nodes rooted at the main module, with a search field to look up modules by path. The graph is
+// embedded in the same format as the output of WriteJSON.
+var htmlTemplate = template.Must(template.New("graph").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Dependency graph of {{ .Main }}</title>
+<style>
+  body { font-family: sans-serif; margin: 2em; }
+  input { width: 30em; padding: 0.3em; margin-bottom: 1em; }
+  ul { list-style: none; padding-left: 1.5em; margin: 0; }
+  li { margin: 0.2em 0; }
+  .toggle { display: inline-block; width: 1em; cursor: pointer; user-select: none; }
+  .version, .replace { color: #666; font-size: 0.9em; margin-left: 0.5em; }
+  .main { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{ .Main }}</h1>
+<input id="search" type="search" placeholder="Search modules by path">
+<div id="tree"></div>
+<script>
+var graph = {{ .Graph }};
+
+var modules = {};
+var successors = {};
+graph.modules.forEach(function (module) {
+  modules[module.path] = module;
+  successors[module.path] = [];
+});
+graph.edges.forEach(function (edge) {
+  successors[edge.from].push(edge.to);
+});
+
+// Children are only rendered when a node is expanded so that shared and cyclic dependencies do
+// not result in an unbounded tree.
+function renderNode(path) {
+  var module = modules[path];
+  var item = document.createElement("li");
+  var toggle = document.createElement("span");
+  toggle.className = "toggle";
+  item.appendChild(toggle);
+
+  var name = document.createElement("span");
+  name.textContent = path;
+  if (module.main) {
+    name.className = "main";
+  }
+  item.appendChild(name);
+  if (module.version) {
+    var version = document.createElement("span");
+    version.className = "version";
+    version.textContent = module.version;
+    item.appendChild(version);
+  }
+  if (module.replace) {
+    var replace = document.createElement("span");
+    replace.className = "replace";
+    replace.textContent = "=> " + module.replace.path + (module.replace.version ? " " + module.replace.version : "");
+    item.appendChild(replace);
+  }
+
+  if (successors[path].length > 0) {
+    var children = null;
+    toggle.textContent = "+";
+    toggle.onclick = function () {
+      if (children === null) {
+        children = renderList(successors[path]);
+        item.appendChild(children);
+        toggle.textContent = "-";
+      } else {
+        item.removeChild(children);
+        children = null;
+        toggle.textContent = "+";
+      }
+    };
+  }
+  return item;
+}
+
+function renderList(paths) {
+  var list = document.createElement("ul");
+  paths.forEach(function (path) {
+    list.appendChild(renderNode(path));
+  });
+  return list;
+}
+
+function render() {
+  var query = document.getElementById("search").value.toLowerCase();
+  var roots = graph.modules.filter(function (module) {
+    return query === "" ? module.main : module.path.toLowerCase().indexOf(query) >= 0;
+  }).map(function (module) {
+    return module.path;
+  });
+  var tree = document.getElementById("tree");
+  tree.innerHTML = "";
+  tree.appendChild(renderList(roots));
+}
+
+document.getElementById("search").oninput = render;
+render();
+</script>
+</body>
+</html>
+`))
+
+// WriteHTML writes a self-contained HTML page to the specified writer that allows to explore the
+// DepGraph in a browser. Modules are shown as a tree rooted at the main module in which each node
+// can be expanded to show its dependencies. A search field allows to look up modules by path.
+func (g *DepGraph) WriteHTML(w io.Writer) error {
+	data := struct {
+		Main  string
+		Graph *jsonGraph
+	}{
+		Main:  g.main.Name(),
+		Graph: g.toJSON(),
+	}
+	if err := htmlTemplate.Execute(w, data); err != nil {
+		g.logger.WithError(err).Error("Failed to write HTML graph.")
+		return fmt.Errorf("could not write HTML graph: %v", err)
+	}
+	return nil
+}
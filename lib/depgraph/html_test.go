@@ -0,0 +1,27 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteHTML(t *testing.T) {
+	writer := &strings.Builder{}
+	assert.NoError(t, testGraph().WriteHTML(writer), "Should not fail to write HTML.")
+
+	output := writer.String()
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"), "Should write an HTML document.")
+	assert.Contains(t, output, "<title>Dependency graph of test/module</title>")
+	assert.Contains(t, output, `"path":"moduleC","version":"v0.2.0","main":false,"replace":{"path":"moduleC-fork","version":"v0.2.1"}`)
+	assert.Contains(t, output, `{"from":"test/module","to":"moduleA"}`)
+	assert.NotContains(t, output, "<script src", "Should not depend on external scripts.")
+}
+
+func Test_WriteHTMLEscaping(t *testing.T) {
+	graph := NewGraph(nil, &Module{Main: true, Path: "</script><b>"})
+	writer := &strings.Builder{}
+	assert.NoError(t, graph.WriteHTML(writer))
+	assert.NotContains(t, writer.String(), "</script><b>", "Should escape module paths.")
+}
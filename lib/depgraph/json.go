@@ -0,0 +1,89 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type jsonGraph struct {
+	Modules []jsonModule `json:"modules"`
+	Edges   []jsonEdge   `json:"edges"`
+}
+
+type jsonModule struct {
+	Path    string       `json:"path"`
+	Version string       `json:"version,omitempty"`
+	Main    bool         `json:"main"`
+	Replace *jsonReplace `json:"replace,omitempty"`
+}
+
+type jsonReplace struct {
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+type jsonEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// WriteJSON writes a JSON representation of the DepGraph to the specified writer. The output
+// contains a 'modules' array with the coordinates of each module and, if applicable, of its
+// replacement as well as an 'edges' array of the dependencies between them. Both arrays are sorted
+// so that the output is stable.
+func (g *DepGraph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(g.toJSON()); err != nil {
+		g.logger.WithError(err).Error("Failed to write JSON graph.")
+		return fmt.Errorf("could not write JSON graph: %v", err)
+	}
+	return nil
+}
+
+func (g *DepGraph) toJSON() *jsonGraph {
+	output := &jsonGraph{
+		Modules: []jsonModule{},
+		Edges:   []jsonEdge{},
+	}
+	for _, node := range g.sortedNodes() {
+		module := jsonModule{
+			Path:    node.Module.Path,
+			Version: node.Module.Version,
+			Main:    node.Module.Main,
+		}
+		if node.Module.Replace != nil {
+			module.Replace = &jsonReplace{
+				Path:    node.Module.Replace.Path,
+				Version: node.Module.Replace.Version,
+			}
+		}
+		output.Modules = append(output.Modules, module)
+
+		for _, dep := range node.sortedSuccessors() {
+			output.Edges = append(output.Edges, jsonEdge{From: dep.begin, To: dep.end})
+		}
+	}
+	return output
+}
+
+// sortedNodes returns all the nodes of the DepGraph ordered by their name.
+func (g *DepGraph) sortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i int, j int) bool { return nodes[i].Name() < nodes[j].Name() })
+	return nodes
+}
+
+// sortedSuccessors returns the outgoing dependencies of the Node ordered by the name of the module
+// that they require.
+func (n *Node) sortedSuccessors() []*Dependency {
+	successors := make([]*Dependency, len(n.successors))
+	copy(successors, n.successors)
+	sort.Slice(successors, func(i int, j int) bool { return successors[i].end < successors[j].end })
+	return successors
+}
@@ -0,0 +1,70 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteJSON(t *testing.T) {
+	const expectedOutput = `{
+  "modules": [
+    {
+      "path": "moduleA",
+      "version": "v1.0.0",
+      "main": false
+    },
+    {
+      "path": "moduleB",
+      "version": "v1.1.0",
+      "main": false
+    },
+    {
+      "path": "moduleC",
+      "version": "v0.2.0",
+      "main": false,
+      "replace": {
+        "path": "moduleC-fork",
+        "version": "v0.2.1"
+      }
+    },
+    {
+      "path": "moduleD",
+      "version": "v2.0.0",
+      "main": false
+    },
+    {
+      "path": "test/module",
+      "main": true
+    }
+  ],
+  "edges": [
+    {
+      "from": "moduleA",
+      "to": "moduleC"
+    },
+    {
+      "from": "moduleB",
+      "to": "moduleC"
+    },
+    {
+      "from": "moduleC",
+      "to": "moduleD"
+    },
+    {
+      "from": "test/module",
+      "to": "moduleA"
+    },
+    {
+      "from": "test/module",
+      "to": "moduleB"
+    }
+  ]
+}
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testGraph().WriteJSON(writer), "Should not fail to write JSON.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected JSON.")
+}
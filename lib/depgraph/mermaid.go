@@ -0,0 +1,63 @@
+package depgraph
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var mermaidUnsafeRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// WriteMermaid writes the DepGraph as a Mermaid flowchart to the specified writer. Each module is
+// rendered as a node labelled with its path and, on a second line, its selected version. The main
+// module is given the 'main' style class so that it stands out.
+func (g *DepGraph) WriteMermaid(w io.Writer) error {
+	nodes := g.sortedNodes()
+	ids := mermaidNodeIDs(nodes)
+
+	lines := []string{"graph LR"}
+	for _, node := range nodes {
+		label := node.Name()
+		if version := node.SelectedVersion(); version != "" {
+			label += "<br/>" + version
+		}
+		line := fmt.Sprintf("  %s[\"%s\"]", ids[node.Name()], label)
+		if node.Name() == g.main.Name() {
+			line += ":::main"
+		}
+		lines = append(lines, line)
+	}
+	for _, node := range nodes {
+		for _, dep := range node.sortedSuccessors() {
+			lines = append(lines, fmt.Sprintf("  %s --> %s", ids[dep.begin], ids[dep.end]))
+		}
+	}
+	lines = append(lines, "  classDef main font-weight:bold,stroke-width:3px")
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		g.logger.WithError(err).Error("Failed to write Mermaid graph.")
+		return fmt.Errorf("could not write Mermaid graph: %v", err)
+	}
+	return nil
+}
+
+// mermaidNodeIDs computes a unique identifier for each node that only contains characters which
+// Mermaid accepts in node IDs. Paths that map onto an already used identifier get a numeric suffix.
+func mermaidNodeIDs(nodes []*Node) map[string]string {
+	ids := map[string]string{}
+	used := map[string]struct{}{}
+	for _, node := range nodes {
+		base := mermaidUnsafeRE.ReplaceAllString(node.Name(), "_")
+		id := base
+		for idx := 1; ; idx++ {
+			if _, ok := used[id]; !ok {
+				break
+			}
+			id = fmt.Sprintf("%s_%d", base, idx)
+		}
+		used[id] = struct{}{}
+		ids[node.Name()] = id
+	}
+	return ids
+}
@@ -0,0 +1,39 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteMermaid(t *testing.T) {
+	const expectedOutput = `graph LR
+  moduleA["moduleA<br/>v1.0.0"]
+  moduleB["moduleB<br/>v1.1.0"]
+  moduleC["moduleC<br/>v0.2.1"]
+  moduleD["moduleD<br/>v2.0.0"]
+  test_module["test/module"]:::main
+  moduleA --> moduleC
+  moduleB --> moduleC
+  moduleC --> moduleD
+  test_module --> moduleA
+  test_module --> moduleB
+  classDef main font-weight:bold,stroke-width:3px
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testGraph().WriteMermaid(writer), "Should not fail to write Mermaid.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected Mermaid graph.")
+}
+
+func Test_MermaidNodeIDs(t *testing.T) {
+	nodes := []*Node{
+		{Module: &Module{Path: "github.com/foo/bar-baz"}},
+		{Module: &Module{Path: "github.com/foo/bar.baz"}},
+	}
+	assert.Equal(t, map[string]string{
+		"github.com/foo/bar-baz": "github_com_foo_bar_baz",
+		"github.com/foo/bar.baz": "github_com_foo_bar_baz_1",
+	}, mermaidNodeIDs(nodes), "Should have generated unique and sanitized IDs.")
+}
@@ -0,0 +1,48 @@
+package depgraph
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// BuildForPackage creates the dependency graph of the main module and restricts it to the modules
+// that provide the specified package or any of the packages it transitively imports, as reported
+// by 'go list -deps'. The main module is always retained. This shows which modules are required by
+// a single package, such as one command of a module that provides several. The 'logger' parameter
+// can be 'nil' which will result in no output or logging information to be provided.
+func BuildForPackage(logger *logrus.Logger, pkg string) (*DepGraph, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	graph, err := GetDepGraph(logger, true)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Retrieving the dependencies of package %q via 'go list'", pkg)
+	raw, err := util.RunCommand(logger, true, "go", "list", "-deps", "-json", pkg)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve the dependencies of package %q: %v", pkg, err)
+	}
+	packages, err := parsePackageList(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	return graph.forPackages(packages), nil
+}
+
+// forPackages returns a copy of the DepGraph that only retains the modules providing at least one
+// of the specified packages.
+func (g *DepGraph) forPackages(packages []*goPackage) *DepGraph {
+	nodes := g.packageNodes(packages)
+	return g.retain(func(node *Node) bool {
+		_, ok := nodes[node]
+		return ok
+	})
+}
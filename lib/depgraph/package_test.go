@@ -0,0 +1,38 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ForPackages(t *testing.T) {
+	const listOutput = `{
+	"ImportPath": "fmt"
+}
+{
+	"ImportPath": "moduleD/pkg",
+	"Module": {"Path": "moduleD"}
+}
+{
+	"ImportPath": "moduleC-fork/pkg",
+	"Module": {"Path": "moduleC", "Replace": {"Path": "moduleC-fork"}},
+	"Imports": ["moduleD/pkg"]
+}
+{
+	"ImportPath": "test/module/cmd",
+	"Module": {"Path": "test/module", "Main": true},
+	"Imports": ["fmt", "moduleC-fork/pkg"]
+}
+`
+	packages, err := parsePackageList(strings.NewReader(listOutput))
+	require.NoError(t, err)
+
+	graph := testGraph()
+	scoped := graph.forPackages(packages)
+	assert.Equal(t, []string{"moduleC", "moduleD", "test/module"}, nodeNames(scoped))
+	assert.Len(t, scoped.Node("moduleC").Successors(), 1, "Should retain the dependencies between retained modules.")
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+}
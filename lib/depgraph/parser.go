@@ -1,10 +1,12 @@
 package depgraph
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"regexp"
 	"strings"
@@ -26,12 +28,17 @@ func GetDepGraph(logger *logrus.Logger, quiet bool) (*DepGraph, error) {
 	}
 	logger.Debug("Creating dependency graph.")
 
-	mainModule, modules, err := getSelectedModules(logger, quiet)
+	mainModule, modules, duplicates, err := getSelectedModules(logger, quiet)
 	if err != nil {
 		return nil, err
 	}
 
 	graph := NewGraph(logger, mainModule)
+	for _, versions := range duplicates {
+		for _, module := range versions[1:] {
+			graph.recordDuplicate(versions[0], module)
+		}
+	}
 
 	logger.Debug("Retrieving dependency information via 'go mod graph'")
 	rawDeps, err := util.RunCommand(logger, quiet, "go", "mod", "graph")
@@ -39,37 +46,111 @@ func GetDepGraph(logger *logrus.Logger, quiet bool) (*DepGraph, error) {
 		return nil, err
 	}
 
-	for _, depString := range strings.Split(strings.TrimSpace(string(rawDeps)), "\n") {
-		graph.logger.Debugf("Parsing dependency: %s", depString)
-		rawDep, ok := graph.parseDependency(depString, modules)
+	if err = graph.addDependencies(strings.Split(strings.TrimSpace(string(rawDeps)), "\n"), modules); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+// ParseModGraph creates a dependency graph based on the output of 'go mod graph' which is read from
+// the provided reader. This allows to analyse a previously recorded graph without requiring access
+// to the module itself. As no other information is available the selected version of each module
+// is determined as the highest version that appears in the graph. The 'logger' parameter can be
+// 'nil' which will result in no output or logging information to be provided.
+func ParseModGraph(logger *logrus.Logger, r io.Reader) (*DepGraph, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+	logger.Debug("Creating dependency graph from 'go mod graph' output.")
+
+	var depStrings []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			depStrings = append(depStrings, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read 'go mod graph' output: %v", err)
+	}
+
+	mainModule, modules, err := selectModulesFromModGraph(logger, depStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := NewGraph(logger, mainModule)
+	if err = graph.addDependencies(depStrings, modules); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+func selectModulesFromModGraph(logger *logrus.Logger, depStrings []string) (*Module, map[string]*Module, error) {
+	var main *Module
+	modules := map[string]*Module{}
+	for _, depString := range depStrings {
+		depContent := depRE.FindStringSubmatch(depString)
+		if len(depContent) == 0 {
+			continue
+		}
+		for _, coordinates := range [][2]string{{depContent[1], depContent[2]}, {depContent[3], depContent[4]}} {
+			path, version := coordinates[0], coordinates[1]
+			if version == "" {
+				if main == nil {
+					logger.Debugf("Determined main module to be %q.", path)
+					main = &Module{Main: true, Path: path}
+					modules[path] = main
+				}
+				continue
+			}
+			if module, ok := modules[path]; !ok || (!module.Main && moduleMoreRecentThan(version, module.Version)) {
+				modules[path] = &Module{Path: path, Version: version}
+			}
+		}
+	}
+	if main == nil {
+		return nil, nil, errors.New("could not determine main module")
+	}
+	return main, modules, nil
+}
+
+func (g *DepGraph) addDependencies(depStrings []string, modules map[string]*Module) error {
+	seen := map[string]struct{}{}
+	for _, depString := range depStrings {
+		g.logger.Debugf("Parsing dependency: %s", depString)
+		rawDep, ok := g.parseDependency(depString, modules)
 		if !ok {
 			continue
 		}
-		if err = graph.addDependency(rawDep); err != nil {
-			return nil, err
+		edge := rawDep.beginModule.Path + " " + rawDep.endModule.Path
+		if _, duplicate := seen[edge]; duplicate {
+			g.logger.Debugf("Skipping duplicate dependency: %s", depString)
+			continue
+		}
+		seen[edge] = struct{}{}
+		if err := g.addDependency(rawDep); err != nil {
+			return err
 		}
 	}
-	for _, node := range graph.nodes {
+	for _, node := range g.nodes {
 		if len(node.predecessors) == 0 && len(node.successors) == 0 {
-			graph.removeNode(node.Name())
+			g.removeNode(node.Name())
 		}
 	}
-	return graph, nil
+	return nil
 }
 
-func getSelectedModules(logger *logrus.Logger, quiet bool) (*Module, map[string]*Module, error) {
+func getSelectedModules(logger *logrus.Logger, quiet bool) (*Module, map[string]*Module, map[string][]*Module, error) {
 	logger.Debug("Retrieving module information via 'go list'")
 	raw, err := util.RunCommand(logger, quiet, "go", "list", "-json", "-m", "all")
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	raw = bytes.ReplaceAll(bytes.TrimSpace(raw), []byte("\n}\n"), []byte("\n},\n"))
-	raw = append([]byte("[\n"), raw...)
-	raw = append(raw, []byte("\n]")...)
-
-	var moduleList []*Module
-	if err = json.Unmarshal(raw, &moduleList); err != nil {
-		return nil, nil, fmt.Errorf("Unable to retrieve information from 'go list': %v", err)
+	moduleList, err := parseModuleList(raw)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	var main *Module
@@ -88,9 +169,45 @@ func getSelectedModules(logger *logrus.Logger, quiet bool) (*Module, map[string]
 		}
 	}
 	if main == nil || len(main.Path) == 0 {
-		return nil, nil, errors.New("could not determine main module")
+		return nil, nil, nil, errors.New("could not determine main module")
 	}
-	return main, modules, nil
+	return main, modules, duplicateModules(moduleList), nil
+}
+
+// duplicateModules returns the module paths that appear at more than one version in the given list
+// of modules, together with these modules in the order in which they appear.
+func duplicateModules(moduleList []*Module) map[string][]*Module {
+	byPath := map[string][]*Module{}
+	for _, module := range moduleList {
+		knownVersion := false
+		for _, other := range byPath[module.Path] {
+			knownVersion = knownVersion || other.Version == module.Version
+		}
+		if !knownVersion {
+			byPath[module.Path] = append(byPath[module.Path], module)
+		}
+	}
+
+	duplicates := map[string][]*Module{}
+	for path, modules := range byPath {
+		if len(modules) > 1 {
+			duplicates[path] = modules
+		}
+	}
+	return duplicates
+}
+
+// parseModuleList decodes the stream of JSON objects that is output by 'go list -json -m'.
+func parseModuleList(raw []byte) ([]*Module, error) {
+	raw = bytes.ReplaceAll(bytes.TrimSpace(raw), []byte("\n}\n"), []byte("\n},\n"))
+	raw = append([]byte("[\n"), raw...)
+	raw = append(raw, []byte("\n]")...)
+
+	var moduleList []*Module
+	if err := json.Unmarshal(raw, &moduleList); err != nil {
+		return nil, fmt.Errorf("Unable to retrieve information from 'go list': %v", err)
+	}
+	return moduleList, nil
 }
 
 func (g *DepGraph) addDependency(rawDependency *rawDependency) error {
@@ -0,0 +1,41 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseModGraph(t *testing.T) {
+	const modGraph = `test/module moduleA@v1.0.0
+test/module moduleB@v1.1.0
+test/module moduleB@v1.1.0
+moduleA@v1.0.0 moduleC@v0.1.0
+moduleA@v1.0.0 test/module@v0.1.0
+moduleB@v1.1.0 moduleC@v0.2.0
+moduleB@v1.0.0 moduleD@v1.0.0
+moduleC@v0.1.0 moduleD@v1.0.0
+`
+
+	graph, err := ParseModGraph(nil, strings.NewReader(modGraph))
+	require.NoError(t, err, "Should be able to parse the graph.")
+
+	assert.Equal(t, &Module{Main: true, Path: "test/module"}, graph.Main().Module, "Should have determined the main module.")
+	assert.Equal(t, []string{"moduleA", "moduleB", "moduleC", "test/module"}, nodeNames(graph), "Should only retain used modules.")
+	assert.Equal(t, "v0.2.0", graph.Node("moduleC").SelectedVersion(), "Should have selected the highest version.")
+	assert.Len(t, graph.Main().Successors(), 2, "Should have deduplicated edges.")
+	assert.Len(t, graph.Node("moduleC").Predecessors(), 2, "Should have recorded all edges.")
+
+	_, err = ParseModGraph(nil, strings.NewReader("moduleA@v1.0.0 moduleB@v1.0.0\n"))
+	assert.Error(t, err, "Should fail without a main module.")
+}
+
+func Test_DuplicateModules(t *testing.T) {
+	moduleCv1 := &Module{Path: "moduleC", Version: "v0.1.0"}
+	assert.Equal(t, map[string][]*Module{
+		"moduleC": {testModuleC, moduleCv1},
+	}, duplicateModules([]*Module{testMainModule, testModuleA, testModuleC, moduleCv1, testModuleC, testModuleA}))
+	assert.Empty(t, duplicateModules([]*Module{testMainModule, testModuleA, testModuleB}))
+}
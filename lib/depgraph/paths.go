@@ -0,0 +1,324 @@
+package depgraph
+
+import (
+	"errors"
+	"sort"
+)
+
+var (
+	// ErrUnknownModule is returned when a queried module is not part of the DepGraph.
+	ErrUnknownModule = errors.New("module is not part of the dependency graph")
+	// ErrNoPath is returned when no chain of dependencies links two modules.
+	ErrNoPath = errors.New("no dependency path exists between the modules")
+	// ErrTooManyPaths is returned when more dependency paths exist between two modules than were
+	// requested.
+	ErrTooManyPaths = errors.New("too many dependency paths exist between the modules")
+)
+
+// ShortestPath returns the shortest chain of dependencies that leads from the 'from' module to the
+// 'to' module, both ends included. If several chains of equal length exist the one that is first
+// when ordering successors by path is returned. ErrUnknownModule is returned if either module is not
+// part of the DepGraph and ErrNoPath if 'to' can not be reached from 'from'.
+func (g *DepGraph) ShortestPath(from string, to string) ([]*Module, error) {
+	fromNode, toNode, err := g.lookupPathEnds(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	g.logger.Debugf("Searching shortest path from %q to %q.", fromNode.Name(), toNode.Name())
+	parents := map[string]string{fromNode.Name(): ""}
+	todo := []*Node{fromNode}
+	for len(todo) > 0 && todo[0] != toNode {
+		for _, dep := range todo[0].sortedSuccessors() {
+			if _, visited := parents[dep.end]; !visited {
+				parents[dep.end] = dep.begin
+				todo = append(todo, g.nodes[dep.end])
+			}
+		}
+		todo = todo[1:]
+	}
+	if len(todo) == 0 {
+		g.logger.Debugf("Module %q can not be reached from %q.", toNode.Name(), fromNode.Name())
+		return nil, ErrNoPath
+	}
+
+	var path []*Module
+	for name := toNode.Name(); name != ""; name = parents[name] {
+		path = append([]*Module{g.nodes[name].Module}, path...)
+	}
+	return path, nil
+}
+
+// AllPaths returns every chain of dependencies that leads from the 'from' module to the 'to' module
+// without visiting the same module twice. Paths are ordered as they are found when traversing
+// successors by path. At most 'maxPaths' paths are returned, a value of zero or less meaning that
+// there is no limit. If further paths exist beyond the limit the ones found so far are returned
+// together with ErrTooManyPaths. ErrUnknownModule is returned if either module is not part of the
+// DepGraph and ErrNoPath if 'to' can not be reached from 'from'.
+func (g *DepGraph) AllPaths(from string, to string, maxPaths int) ([][]*Module, error) {
+	fromNode, toNode, err := g.lookupPathEnds(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	g.logger.Debugf("Searching all paths from %q to %q.", fromNode.Name(), toNode.Name())
+	finder := &pathFinder{
+		graph:     g,
+		target:    toNode,
+		ancestors: g.ancestors(toNode),
+		maxPaths:  maxPaths,
+		onPath:    map[string]bool{},
+	}
+	if _, ok := finder.ancestors[fromNode.Name()]; ok {
+		finder.visit(fromNode)
+	}
+
+	if len(finder.paths) == 0 {
+		g.logger.Debugf("Module %q can not be reached from %q.", toNode.Name(), fromNode.Name())
+		return nil, ErrNoPath
+	}
+	if finder.truncated {
+		g.logger.Warnf("Only returning the first %d paths from %q to %q.", maxPaths, fromNode.Name(), toNode.Name())
+		return finder.paths, ErrTooManyPaths
+	}
+	return finder.paths, nil
+}
+
+type pathFinder struct {
+	graph     *DepGraph
+	target    *Node
+	ancestors map[string]struct{}
+	maxPaths  int
+	current   []*Module
+	onPath    map[string]bool
+	paths     [][]*Module
+	truncated bool
+}
+
+// visit extends the current path with the given node and explores all its successors from which the
+// target can be reached. It returns false once the search has been aborted because the maximum
+// number of paths has been exceeded.
+func (f *pathFinder) visit(node *Node) bool {
+	f.current = append(f.current, node.Module)
+	f.onPath[node.Name()] = true
+	defer func() {
+		f.current = f.current[:len(f.current)-1]
+		delete(f.onPath, node.Name())
+	}()
+
+	if node == f.target {
+		if f.maxPaths > 0 && len(f.paths) == f.maxPaths {
+			f.truncated = true
+			return false
+		}
+		f.paths = append(f.paths, append([]*Module(nil), f.current...))
+		return true
+	}
+	for _, dep := range node.sortedSuccessors() {
+		if _, ok := f.ancestors[dep.end]; !ok || f.onPath[dep.end] {
+			continue
+		}
+		if !f.visit(f.graph.nodes[dep.end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// LongestChain returns the longest chain of dependencies that starts at the main module without
+// visiting the same module twice, both ends included. If several chains of equal length exist the
+// one that is found first when traversing successors by path is returned. When no cycle can be
+// reached from the main module this is computed in linear time. Otherwise all such chains need to
+// be explored which can take very long for large graphs.
+func (g *DepGraph) LongestChain() []*Module {
+	if chain, ok := g.longestAcyclicChain(g.main, map[string][]*Module{}); ok {
+		return chain
+	}
+
+	g.logger.Debug("Exploring all dependency chains as the dependency graph contains cycles.")
+	finder := &chainFinder{graph: g, onPath: map[string]bool{}}
+	finder.visit(g.main)
+	return finder.longest
+}
+
+// longestAcyclicChain returns the longest chain starting at the given node. The chains of nodes
+// that have been visited are stored in 'chains', with a nil entry for nodes whose successors are
+// still being visited. If a cycle is encountered 'false' is returned instead.
+func (g *DepGraph) longestAcyclicChain(node *Node, chains map[string][]*Module) ([]*Module, bool) {
+	if chain, visited := chains[node.Name()]; visited {
+		return chain, chain != nil
+	}
+	chains[node.Name()] = nil
+
+	var longest []*Module
+	for _, dep := range node.sortedSuccessors() {
+		chain, ok := g.longestAcyclicChain(g.nodes[dep.end], chains)
+		if !ok {
+			return nil, false
+		}
+		if len(chain) > len(longest) {
+			longest = chain
+		}
+	}
+	chain := append([]*Module{node.Module}, longest...)
+	chains[node.Name()] = chain
+	return chain, true
+}
+
+type chainFinder struct {
+	graph   *DepGraph
+	current []*Module
+	onPath  map[string]bool
+	longest []*Module
+}
+
+// visit extends the current chain with the given node and explores all its successors that are not
+// yet part of the chain.
+func (f *chainFinder) visit(node *Node) {
+	f.current = append(f.current, node.Module)
+	f.onPath[node.Name()] = true
+	defer func() {
+		f.current = f.current[:len(f.current)-1]
+		delete(f.onPath, node.Name())
+	}()
+
+	if len(f.current) > len(f.longest) {
+		f.longest = append([]*Module(nil), f.current...)
+	}
+	for _, dep := range node.sortedSuccessors() {
+		if !f.onPath[dep.end] {
+			f.visit(f.graph.nodes[dep.end])
+		}
+	}
+}
+
+// Dependents returns all the modules that depend on the specified module, either directly or
+// transitively, ordered by their path. The module itself is not part of the result, even when it is
+// part of a dependency cycle. ErrUnknownModule is returned if the module is not part of the
+// DepGraph.
+func (g *DepGraph) Dependents(path string) ([]*Module, error) {
+	target := g.Node(path)
+	if target == nil {
+		g.logger.Errorf("Module %q is not part of the dependency graph.", path)
+		return nil, ErrUnknownModule
+	}
+
+	g.logger.Debugf("Searching modules that depend on %q.", target.Name())
+	var dependents []*Module
+	for name := range g.ancestors(target) {
+		if name != target.Name() {
+			dependents = append(dependents, g.nodes[name].Module)
+		}
+	}
+	sort.Slice(dependents, func(i int, j int) bool { return dependents[i].Path < dependents[j].Path })
+	return dependents, nil
+}
+
+// ancestors returns the names of the target node and of all the nodes from which it can be reached.
+func (g *DepGraph) ancestors(target *Node) map[string]struct{} {
+	visited := map[string]struct{}{target.Name(): {}}
+	todo := []*Node{target}
+	for len(todo) > 0 {
+		for _, dep := range todo[0].predecessors {
+			if _, ok := visited[dep.begin]; !ok {
+				visited[dep.begin] = struct{}{}
+				todo = append(todo, g.nodes[dep.begin])
+			}
+		}
+		todo = todo[1:]
+	}
+	return visited
+}
+
+// UniqueDependencies returns the modules that are only reachable from the main module through the
+// specified module, ordered by path. These are the modules that would no longer be part of the
+// build if the specified module were to be dropped. ErrUnknownModule is returned if the module is
+// not part of the DepGraph.
+func (g *DepGraph) UniqueDependencies(path string) ([]*Module, error) {
+	target := g.Node(path)
+	if target == nil {
+		g.logger.Errorf("Module %q is not part of the dependency graph.", path)
+		return nil, ErrUnknownModule
+	}
+
+	g.logger.Debugf("Searching modules that are only required via %q.", target.Name())
+	withTarget := g.reachableFromMain("")
+	withoutTarget := g.reachableFromMain(target.Name())
+
+	var unique []*Module
+	for _, node := range g.sortedNodes() {
+		_, reachable := withTarget[node.Name()]
+		_, alternative := withoutTarget[node.Name()]
+		if node != target && reachable && !alternative {
+			unique = append(unique, node.Module)
+		}
+	}
+	return unique, nil
+}
+
+// reachableFromMain returns the names of all modules that can be reached from the main module
+// without going through the module with the 'skip' name.
+func (g *DepGraph) reachableFromMain(skip string) map[string]struct{} {
+	reachable := map[string]struct{}{}
+	if g.main.Name() == skip {
+		return reachable
+	}
+	reachable[g.main.Name()] = struct{}{}
+	todo := []*Node{g.main}
+	for len(todo) > 0 {
+		for _, dep := range todo[0].successors {
+			if _, visited := reachable[dep.end]; !visited && dep.end != skip {
+				reachable[dep.end] = struct{}{}
+				todo = append(todo, g.nodes[dep.end])
+			}
+		}
+		todo = todo[1:]
+	}
+	return reachable
+}
+
+func (g *DepGraph) lookupPathEnds(from string, to string) (*Node, *Node, error) {
+	fromNode := g.Node(from)
+	if fromNode == nil {
+		g.logger.Errorf("Module %q is not part of the dependency graph.", from)
+		return nil, nil, ErrUnknownModule
+	}
+	toNode := g.Node(to)
+	if toNode == nil {
+		g.logger.Errorf("Module %q is not part of the dependency graph.", to)
+		return nil, nil, ErrUnknownModule
+	}
+	return fromNode, toNode, nil
+}
+
+// Unreachable returns the modules of the DepGraph that can not be reached from the main module,
+// ordered by path. This is normally empty for a graph that was built for the main module.
+func (g *DepGraph) Unreachable() []*Module {
+	depths := g.Depths()
+
+	var unreachable []*Module
+	for _, node := range g.sortedNodes() {
+		if _, ok := depths[node.Name()]; !ok {
+			unreachable = append(unreachable, node.Module)
+		}
+	}
+	return unreachable
+}
+
+// Depths computes the minimal number of dependency hops that separate each module from the main
+// module, which itself has a depth of 0. Modules that can not be reached from the main module are
+// not part of the result. Dependency cycles are handled as each module is only visited once.
+func (g *DepGraph) Depths() map[string]int {
+	depths := map[string]int{g.main.Name(): 0}
+	todo := []*Node{g.main}
+	for len(todo) > 0 {
+		for _, dep := range todo[0].successors {
+			if _, visited := depths[dep.end]; !visited {
+				depths[dep.end] = depths[dep.begin] + 1
+				todo = append(todo, g.nodes[dep.end])
+			}
+		}
+		todo = todo[1:]
+	}
+	return depths
+}
@@ -0,0 +1,249 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ShortestPath(t *testing.T) {
+	testcases := map[string]struct {
+		from          string
+		to            string
+		expectedPath  []*Module
+		expectedError error
+	}{
+		"Direct": {
+			from:         "test/module",
+			to:           "moduleA",
+			expectedPath: []*Module{testMainModule, testModuleA},
+		},
+		"Transitive": {
+			from:         "test/module",
+			to:           "moduleD",
+			expectedPath: []*Module{testMainModule, testModuleA, testModuleC, testModuleD},
+		},
+		"Replaced": {
+			from:         "moduleB",
+			to:           "moduleC-fork",
+			expectedPath: []*Module{testModuleB, testModuleC},
+		},
+		"Self": {
+			from:         "moduleC",
+			to:           "moduleC",
+			expectedPath: []*Module{testModuleC},
+		},
+		"Unreachable": {
+			from:          "moduleD",
+			to:            "test/module",
+			expectedError: ErrNoPath,
+		},
+		"Unknown": {
+			from:          "test/module",
+			to:            "moduleE",
+			expectedError: ErrUnknownModule,
+		},
+	}
+
+	graph := testGraph()
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			path, err := graph.ShortestPath(tc.from, tc.to)
+			assert.Equal(t, tc.expectedError, err, "Should return the expected error.")
+			assert.Equal(t, tc.expectedPath, path, "Should return the expected path.")
+		})
+	}
+}
+
+func Test_AllPaths(t *testing.T) {
+	testcases := map[string]struct {
+		from          string
+		to            string
+		maxPaths      int
+		expectedPaths [][]*Module
+		expectedError error
+	}{
+		"Diamond": {
+			from: "test/module",
+			to:   "moduleD",
+			expectedPaths: [][]*Module{
+				{testMainModule, testModuleA, testModuleC, testModuleD},
+				{testMainModule, testModuleB, testModuleC, testModuleD},
+			},
+		},
+		"WithinLimit": {
+			from:     "test/module",
+			to:       "moduleC-fork",
+			maxPaths: 2,
+			expectedPaths: [][]*Module{
+				{testMainModule, testModuleA, testModuleC},
+				{testMainModule, testModuleB, testModuleC},
+			},
+		},
+		"Truncated": {
+			from:          "test/module",
+			to:            "moduleD",
+			maxPaths:      1,
+			expectedPaths: [][]*Module{{testMainModule, testModuleA, testModuleC, testModuleD}},
+			expectedError: ErrTooManyPaths,
+		},
+		"Self": {
+			from:          "moduleC",
+			to:            "moduleC",
+			expectedPaths: [][]*Module{{testModuleC}},
+		},
+		"Unreachable": {
+			from:          "moduleD",
+			to:            "test/module",
+			expectedError: ErrNoPath,
+		},
+		"Unknown": {
+			from:          "test/module",
+			to:            "moduleE",
+			expectedError: ErrUnknownModule,
+		},
+	}
+
+	graph := testGraph()
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			paths, err := graph.AllPaths(tc.from, tc.to, tc.maxPaths)
+			assert.Equal(t, tc.expectedError, err, "Should return the expected error.")
+			assert.Equal(t, tc.expectedPaths, paths, "Should return the expected paths.")
+		})
+	}
+}
+
+func Test_AllPathsCycle(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+		},
+	)
+	paths, err := graph.AllPaths("test/module", "moduleB", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]*Module{{testMainModule, testModuleA, testModuleB}}, paths, "Should not loop through cycles.")
+}
+
+func Test_AllPathsLargeGraph(t *testing.T) {
+	graph := layeredTestGraph(100, 3)
+	graph.AddNode(testModuleA)
+	assert.NoError(t, graph.AddEdge("test/module", "moduleA"))
+	assert.NoError(t, graph.AddEdge("layer000/module0", "moduleA"))
+
+	paths, err := graph.AllPaths("test/module", "moduleA", 1)
+	assert.Equal(t, ErrTooManyPaths, err)
+	expected := [][]*Module{{testMainModule, graph.Node("layer000/module0").Module, testModuleA}}
+	assert.Equal(t, expected, paths, "Should only explore modules that lead to the target.")
+
+	paths, err = graph.AllPaths("test/module", "layer002/module1", 2)
+	assert.Equal(t, ErrTooManyPaths, err)
+	assert.Len(t, paths, 2, "Should stop the search after reaching the limit.")
+}
+
+func Test_Dependents(t *testing.T) {
+	graph := testGraph()
+
+	dependents, err := graph.Dependents("moduleC-fork")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleA, testModuleB, testMainModule}, dependents)
+
+	dependents, err = graph.Dependents("test/module")
+	assert.NoError(t, err)
+	assert.Empty(t, dependents, "Should not find dependents of the main module.")
+
+	_, err = graph.Dependents("moduleE")
+	assert.Equal(t, ErrUnknownModule, err)
+
+	cyclic := newTestGraph(
+		[]*Module{testModuleA, testModuleB},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+		},
+	)
+	dependents, err = cyclic.Dependents("moduleA")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleB, testMainModule}, dependents, "Should handle cycles through the target.")
+}
+
+func Test_UniqueDependencies(t *testing.T) {
+	graph := testGraph()
+
+	unique, err := graph.UniqueDependencies("moduleA")
+	assert.NoError(t, err)
+	assert.Empty(t, unique, "Should account for alternative paths of diamond dependencies.")
+
+	unique, err = graph.UniqueDependencies("moduleC-fork")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleD}, unique)
+
+	graph.RemoveNode("moduleB")
+	unique, err = graph.UniqueDependencies("moduleA")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleC, testModuleD}, unique)
+
+	_, err = graph.UniqueDependencies("moduleE")
+	assert.Equal(t, ErrUnknownModule, err)
+}
+
+func Test_Depths(t *testing.T) {
+	assert.Equal(t, map[string]int{
+		"test/module": 0,
+		"moduleA":     1,
+		"moduleB":     1,
+		"moduleC":     2,
+		"moduleD":     3,
+	}, testGraph().Depths())
+
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+		},
+	)
+	assert.Equal(t, map[string]int{
+		"test/module": 0,
+		"moduleA":     1,
+		"moduleB":     2,
+	}, graph.Depths(), "Should handle cycles and omit unreachable modules.")
+}
+
+func Test_Unreachable(t *testing.T) {
+	assert.Empty(t, testGraph().Unreachable())
+
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleB", "moduleA"},
+			{"moduleC", "moduleD"},
+			{"moduleD", "moduleC"},
+		},
+	)
+	assert.Equal(t, []*Module{testModuleB, testModuleC, testModuleD}, graph.Unreachable())
+}
+
+func Test_LongestChain(t *testing.T) {
+	assert.Equal(t, []*Module{testMainModule}, NewGraph(nil, testMainModule).LongestChain())
+	assert.Equal(t, []*Module{testMainModule, testModuleA, testModuleC, testModuleD}, testGraph().LongestChain(), "Should pick the first chain found.")
+
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleD"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+			{"moduleD", "moduleB"},
+		},
+	)
+	assert.Equal(t, []*Module{testMainModule, testModuleA, testModuleD, testModuleB, testModuleC}, graph.LongestChain(), "Should handle cycles.")
+}
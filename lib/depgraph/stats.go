@@ -0,0 +1,84 @@
+package depgraph
+
+import (
+	"sort"
+)
+
+// GraphStats contains summary statistics about a DepGraph.
+type GraphStats struct {
+	// Number of modules in the graph, including the main module.
+	ModuleCount int
+	// Number of dependencies between the modules in the graph.
+	EdgeCount int
+	// Largest number of dependency hops required to reach a module from the main module via the
+	// shortest path leading to it.
+	MaxDepth int
+	// Module with the most direct dependents. When several modules have the same number of
+	// dependents the one with the lowest path in alphabetical order is retained.
+	MaxFanInModule *Module
+	// Number of direct dependents of MaxFanInModule.
+	MaxFanIn int
+}
+
+// StatsOptions controls which modules are taken into account by StatsWithOptions.
+type StatsOptions struct {
+	// Do not count the modules that are replaced by a local directory, nor the dependencies from or
+	// to them, so that only modules fetched from elsewhere are counted.
+	ExcludeLocalReplaces bool
+}
+
+// Stats computes summary statistics for the DepGraph.
+func (g *DepGraph) Stats() GraphStats {
+	return g.StatsWithOptions(StatsOptions{})
+}
+
+// StatsWithOptions computes summary statistics for the DepGraph, taking into account only the
+// modules selected by the given StatsOptions. The depth of the counted modules and their number of
+// dependents are still determined based on the full graph.
+func (g *DepGraph) StatsWithOptions(opts StatsOptions) GraphStats {
+	g.logger.Debug("Computing graph statistics.")
+
+	counted := func(node *Node) bool {
+		return !opts.ExcludeLocalReplaces || !node.Module.IsLocalReplace()
+	}
+
+	var stats GraphStats
+	for _, node := range g.sortedNodes() {
+		if !counted(node) {
+			continue
+		}
+		stats.ModuleCount++
+		for _, dep := range node.successors {
+			if counted(g.nodes[dep.end]) {
+				stats.EdgeCount++
+			}
+		}
+		if stats.MaxFanInModule == nil || len(node.predecessors) > stats.MaxFanIn {
+			stats.MaxFanInModule = node.Module
+			stats.MaxFanIn = len(node.predecessors)
+		}
+	}
+	for name, depth := range g.Depths() {
+		if depth > stats.MaxDepth && counted(g.nodes[name]) {
+			stats.MaxDepth = depth
+		}
+	}
+	return stats
+}
+
+// ModuleCount associates a module with a count, such as its number of dependents.
+type ModuleCount struct {
+	Module *Module
+	Count  int
+}
+
+// DependentCounts returns the number of direct dependents of each module in the DepGraph. The
+// result is ordered by decreasing number of dependents with ties ordered by module path.
+func (g *DepGraph) DependentCounts() []ModuleCount {
+	counts := make([]ModuleCount, 0, len(g.nodes))
+	for _, node := range g.sortedNodes() {
+		counts = append(counts, ModuleCount{Module: node.Module, Count: len(node.predecessors)})
+	}
+	sort.SliceStable(counts, func(i int, j int) bool { return counts[i].Count > counts[j].Count })
+	return counts
+}
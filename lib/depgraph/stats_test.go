@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Stats(t *testing.T) {
+	t.Run("Diamond", func(t *testing.T) {
+		assert.Equal(t, GraphStats{
+			ModuleCount:    5,
+			EdgeCount:      5,
+			MaxDepth:       3,
+			MaxFanInModule: testModuleC,
+			MaxFanIn:       2,
+		}, testGraph().Stats())
+	})
+
+	t.Run("TieBreakAndCycle", func(t *testing.T) {
+		graph := newTestGraph(
+			[]*Module{testModuleA, testModuleB},
+			[][2]string{
+				{"test/module", "moduleB"},
+				{"moduleB", "moduleA"},
+				{"moduleA", "moduleB"},
+			},
+		)
+		assert.Equal(t, GraphStats{
+			ModuleCount:    3,
+			EdgeCount:      3,
+			MaxDepth:       2,
+			MaxFanInModule: testModuleB,
+			MaxFanIn:       2,
+		}, graph.Stats())
+
+		graph = newTestGraph(
+			[]*Module{testModuleA, testModuleB},
+			[][2]string{
+				{"test/module", "moduleB"},
+				{"test/module", "moduleA"},
+			},
+		)
+		assert.Equal(t, testModuleA, graph.Stats().MaxFanInModule, "Should break ties alphabetically.")
+	})
+}
+
+func Test_DependentCounts(t *testing.T) {
+	assert.Equal(t, []ModuleCount{
+		{Module: testModuleC, Count: 2},
+		{Module: testModuleA, Count: 1},
+		{Module: testModuleB, Count: 1},
+		{Module: testModuleD, Count: 1},
+		{Module: testMainModule, Count: 0},
+	}, testGraph().DependentCounts())
+}
+
+func Test_StatsWithOptions(t *testing.T) {
+	localC := &Module{Path: "moduleC", Version: "v0.2.0", Replace: &Module{Path: "./overrideC"}}
+	graph := newTestGraph(
+		[]*Module{testModuleA, testModuleB, localC, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+		},
+	)
+	assert.Equal(t, graph.Stats(), graph.StatsWithOptions(StatsOptions{}))
+	assert.Equal(t, GraphStats{
+		ModuleCount:    4,
+		EdgeCount:      2,
+		MaxDepth:       3,
+		MaxFanInModule: testModuleA,
+		MaxFanIn:       1,
+	}, graph.StatsWithOptions(StatsOptions{ExcludeLocalReplaces: true}))
+}
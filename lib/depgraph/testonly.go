@@ -0,0 +1,64 @@
+package depgraph
+
+import (
+	"bytes"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// AnnotateTestOnly marks the modules of the DepGraph that are only required by the tests of the main
+// module's packages. The package information is retrieved via 'go list -deps' both with and without
+// the '-test' flag. A module that is required by both the build and the tests is not marked. If the
+// package information can not be retrieved the DepGraph is left untouched. The 'logger' parameter
+// can be 'nil' in which case the DepGraph's own logger is used.
+func (g *DepGraph) AnnotateTestOnly(logger *logrus.Logger) error {
+	if logger == nil {
+		logger = g.logger
+	}
+	logger.Debug("Retrieving build and test package information via 'go list'")
+	rawBuild, err := util.RunCommand(logger, true, "go", "list", "-deps", "-json", "./...")
+	if err != nil {
+		logger.Warn("Could not retrieve package information. Continuing without test-only information.")
+		return nil
+	}
+	rawTest, err := util.RunCommand(logger, true, "go", "list", "-test", "-deps", "-json", "./...")
+	if err != nil {
+		logger.Warn("Could not retrieve test package information. Continuing without test-only information.")
+		return nil
+	}
+	buildPackages, err := parsePackageList(bytes.NewReader(rawBuild))
+	if err != nil {
+		return err
+	}
+	testPackages, err := parsePackageList(bytes.NewReader(rawTest))
+	if err != nil {
+		return err
+	}
+	g.annotateTestOnly(buildPackages, testPackages)
+	return nil
+}
+
+func (g *DepGraph) annotateTestOnly(buildPackages []*goPackage, testPackages []*goPackage) {
+	build, test := g.packageNodes(buildPackages), g.packageNodes(testPackages)
+	for _, node := range g.nodes {
+		_, isTest := test[node]
+		_, isBuild := build[node]
+		node.Module.TestOnly = isTest && !isBuild && node != g.main
+	}
+}
+
+// packageNodes returns the set of nodes that provide at least one of the specified packages.
+func (g *DepGraph) packageNodes(packages []*goPackage) map[*Node]struct{} {
+	nodes := map[*Node]struct{}{}
+	for _, pkg := range packages {
+		if pkg.Module == nil {
+			continue // Packages of the standard library are not part of a module.
+		}
+		if node := g.Node(pkg.Module.Path); node != nil {
+			nodes[node] = struct{}{}
+		}
+	}
+	return nodes
+}
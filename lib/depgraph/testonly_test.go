@@ -0,0 +1,79 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnnotateTestOnly(t *testing.T) {
+	const buildOutput = `{
+	"ImportPath": "moduleA/pkg",
+	"Module": {"Path": "moduleA"}
+}
+{
+	"ImportPath": "test/module",
+	"Module": {"Path": "test/module", "Main": true},
+	"Imports": ["moduleA/pkg"]
+}
+`
+	const testOutput = `{
+	"ImportPath": "moduleA/pkg",
+	"Module": {"Path": "moduleA"}
+}
+{
+	"ImportPath": "moduleC-fork/pkg",
+	"Module": {"Path": "moduleC", "Replace": {"Path": "moduleC-fork"}}
+}
+{
+	"ImportPath": "moduleB/pkg",
+	"Module": {"Path": "moduleB"},
+	"Imports": ["moduleC-fork/pkg"]
+}
+{
+	"ImportPath": "test/module",
+	"Module": {"Path": "test/module", "Main": true},
+	"Imports": ["moduleA/pkg"]
+}
+{
+	"ImportPath": "test/module.test",
+	"Module": {"Path": "test/module", "Main": true},
+	"Imports": ["moduleA/pkg", "moduleB/pkg"]
+}
+`
+	buildPackages, err := parsePackageList(strings.NewReader(buildOutput))
+	require.NoError(t, err)
+	testPackages, err := parsePackageList(strings.NewReader(testOutput))
+	require.NoError(t, err)
+
+	graph := newTestGraph(
+		[]*Module{
+			{Path: "moduleA", Version: "v1.0.0"},
+			{Path: "moduleB", Version: "v1.1.0"},
+			{Path: "moduleC", Version: "v0.2.0", Replace: &Module{Path: "moduleC-fork", Version: "v0.2.1"}},
+			{Path: "moduleD", Version: "v2.0.0"},
+		},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "moduleB"},
+			{"moduleA", "moduleC"},
+			{"moduleB", "moduleC"},
+			{"moduleC", "moduleD"},
+		},
+	)
+	graph.annotateTestOnly(buildPackages, testPackages)
+
+	assert.False(t, graph.Node("test/module").Module.TestOnly, "The main module should never be test-only.")
+	assert.False(t, graph.Node("moduleA").Module.TestOnly, "A module used by the build should not be test-only.")
+	assert.True(t, graph.Node("moduleB").Module.TestOnly)
+	assert.True(t, graph.Node("moduleC").Module.TestOnly, "Should match modules via their replacement.")
+	assert.False(t, graph.Node("moduleD").Module.TestOnly, "A module that is not used at all should not be test-only.")
+
+	pruned := graph.PruneTestOnly()
+	assert.Len(t, pruned.Nodes(), 3)
+	assert.NotNil(t, pruned.Node("moduleA"))
+	assert.NotNil(t, pruned.Node("moduleD"))
+	assert.Len(t, graph.Nodes(), 5, "Should not have modified the original graph.")
+}
@@ -0,0 +1,42 @@
+package depgraph
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// AnnotateUpdates retrieves the newest available version of each module in the DepGraph via
+// 'go list -m -u' and records it as the module's Update. Modules for which no newer version is
+// known keep a nil Update. If the information can not be retrieved, for example because no network
+// access is available, the DepGraph is left untouched. The 'logger' parameter can be 'nil' in which
+// case the DepGraph's own logger is used.
+func (g *DepGraph) AnnotateUpdates(logger *logrus.Logger) error {
+	if logger == nil {
+		logger = g.logger
+	}
+	logger.Debug("Retrieving available module updates via 'go list'")
+	raw, err := util.RunCommand(logger, true, "go", "list", "-json", "-m", "-u", "all")
+	if err != nil {
+		logger.Warn("Could not retrieve available module updates. Continuing without update information.")
+		return nil
+	}
+	moduleList, err := parseModuleList(raw)
+	if err != nil {
+		return err
+	}
+	g.annotateUpdates(moduleList)
+	return nil
+}
+
+func (g *DepGraph) annotateUpdates(moduleList []*Module) {
+	for _, module := range moduleList {
+		if module.Update == nil {
+			continue
+		}
+		if node, ok := g.nodes[module.Path]; ok {
+			g.logger.Debugf("Module %q can be updated to %q.", module.Path, module.Update.Version)
+			node.Module.Update = module.Update
+		}
+	}
+}
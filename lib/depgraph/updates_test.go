@@ -0,0 +1,50 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnnotateUpdates(t *testing.T) {
+	const goListOutput = `{
+	"Path": "test/module",
+	"Main": true
+}
+{
+	"Path": "moduleA",
+	"Version": "v1.0.0",
+	"Update": {
+		"Path": "moduleA",
+		"Version": "v1.2.0"
+	}
+}
+{
+	"Path": "moduleB",
+	"Version": "v1.1.0"
+}
+{
+	"Path": "moduleE",
+	"Version": "v0.1.0",
+	"Update": {
+		"Path": "moduleE",
+		"Version": "v0.2.0"
+	}
+}
+`
+	moduleList, err := parseModuleList([]byte(goListOutput))
+	require.NoError(t, err)
+
+	graph := newTestGraph(
+		[]*Module{{Path: "moduleA", Version: "v1.0.0"}, {Path: "moduleB", Version: "v1.1.0"}},
+		[][2]string{{"test/module", "moduleA"}, {"test/module", "moduleB"}},
+	)
+	graph.annotateUpdates(moduleList)
+
+	if assert.NotNil(t, graph.Node("moduleA").Module.Update, "Should have annotated the available update.") {
+		assert.Equal(t, "v1.2.0", graph.Node("moduleA").Module.Update.Version)
+	}
+	assert.Nil(t, graph.Node("moduleB").Module.Update, "Should not annotate modules without an update.")
+	assert.Nil(t, graph.Node("moduleE"), "Should not add modules that are not part of the graph.")
+}
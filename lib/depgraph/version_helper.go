@@ -2,6 +2,8 @@ package depgraph
 
 import (
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/blang/semver"
 )
@@ -10,8 +12,18 @@ var (
 	// Regular expressions according to https://golang.org/cmd/go/#hdr-Pseudo_versions.
 	versionRE       = regexp.MustCompile(`^v?(\d+\.\d+\.\d+)(?:-(.*))?$`)
 	pseudoVersionRE = regexp.MustCompile(`^(?:(?:.*.)?0.)?(\d{14})-[0-9a-f]{12}$`)
+	// Major version suffixes of module paths, including the one used by 'gopkg.in' modules.
+	majorSuffixRE = regexp.MustCompile(`^(.+)/v(\d+)$`)
+	gopkgSuffixRE = regexp.MustCompile(`^(gopkg\.in/.+)\.v(\d+)$`)
 )
 
+// VersionMoreRecentThan returns whether the 'lhs' module version is more recent than the 'rhs' one
+// according to semantic versioning and the ordering of Go's pseudo-versions. Any valid version is
+// considered more recent than an invalid one.
+func VersionMoreRecentThan(lhs string, rhs string) bool {
+	return moduleMoreRecentThan(lhs, rhs)
+}
+
 func moduleMoreRecentThan(lhs string, rhs string) bool {
 	lhsParsed := versionRE.FindStringSubmatch(lhs)
 	rhsParsed := versionRE.FindStringSubmatch(rhs)
@@ -26,12 +38,67 @@ func moduleMoreRecentThan(lhs string, rhs string) bool {
 	}
 
 	if len(lhsParsed[2]) == 0 || len(rhsParsed[2]) == 0 {
-		// At least one of the version is a release so this comes down to whether it is the LHS.
-		return len(lhsParsed[2]) == 0
+		// At least one of the version is a release so this comes down to whether it is only the LHS.
+		return len(lhsParsed[2]) == 0 && len(rhsParsed[2]) != 0
 	}
 
 	// We are comparing two pre-release versions.
 	pseudoLHS := pseudoVersionRE.FindStringSubmatch(lhsParsed[2])
 	pseudoRHS := pseudoVersionRE.FindStringSubmatch(rhsParsed[2])
-	return pseudoLHS[1] > pseudoRHS[1]
+	if len(pseudoLHS) != 0 && len(pseudoRHS) != 0 {
+		return pseudoLHS[1] > pseudoRHS[1]
+	}
+
+	// At least one of the versions is a regular pre-release so we rely on semantic versioning.
+	lhsSemVer, lhsErr := semver.Parse(lhsParsed[1] + "-" + lhsParsed[2])
+	rhsSemVer, rhsErr := semver.Parse(rhsParsed[1] + "-" + rhsParsed[2])
+	if lhsErr != nil || rhsErr != nil {
+		return lhsParsed[2] > rhsParsed[2]
+	}
+	return lhsSemVer.GT(rhsSemVer)
+}
+
+// isUnstableVersion returns whether the version is a pre-release, which includes pseudo-versions.
+// Build metadata such as the '+incompatible' suffix is not taken into account.
+func isUnstableVersion(version string) bool {
+	if idx := strings.Index(version, "+"); idx >= 0 {
+		version = version[:idx]
+	}
+	parsed := versionRE.FindStringSubmatch(version)
+	return len(parsed) != 0 && parsed[2] != ""
+}
+
+// majorVersion returns the major version number of the given version. For pseudo-versions this is
+// the major version of the release on which they are based, which is 0 if there is none. The second
+// return value indicates whether the version could be parsed.
+func majorVersion(version string) (int, bool) {
+	if idx := strings.Index(version, "+"); idx >= 0 {
+		version = version[:idx]
+	}
+	parsed := versionRE.FindStringSubmatch(version)
+	if len(parsed) == 0 {
+		return 0, false
+	}
+	major, err := strconv.Atoi(strings.SplitN(parsed[1], ".", 2)[0])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
+
+// SplitMajorPath splits a module path in its base path and the major version indicated by its
+// suffix, e.g. 'github.com/foo/bar/v2' results in 'github.com/foo/bar' and 2. Paths without a valid
+// major version suffix are considered to be at major version 1.
+func SplitMajorPath(path string) (string, int) {
+	if parsed := gopkgSuffixRE.FindStringSubmatch(path); len(parsed) != 0 {
+		if major, err := strconv.Atoi(parsed[2]); err == nil {
+			return parsed[1], major
+		}
+	}
+	if parsed := majorSuffixRE.FindStringSubmatch(path); len(parsed) != 0 {
+		if major, err := strconv.Atoi(parsed[2]); err == nil && major >= 2 {
+			return parsed[1], major
+		}
+	}
+	return path, 1
 }
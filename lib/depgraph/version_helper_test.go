@@ -35,6 +35,10 @@ func TestVersionComparison(t *testing.T) {
 		{newer: "v1.0.0-rc1.0.20190101120100-abcdef012345", older: "v1.0.0-0.20190101120000-abcdef012345"},
 		// Non-versioned commit versus older pre-release on same release.
 		{newer: "v1.0.0-0.20190101120100-abcdef012345", older: "v1.0.0-pre.0.20190101120000-abcdef012345"},
+		// Pre-release versus older pre-release on same release.
+		{newer: "v1.0.0-rc2", older: "v1.0.0-rc1"},
+		// Pre-release versus older non-versioned commit.
+		{newer: "v1.0.0-rc1", older: "v1.0.0-0.20190101120000-abcdef012345"},
 	}
 
 	for _, test := range tests {
@@ -44,3 +48,31 @@ func TestVersionComparison(t *testing.T) {
 		assert.Falsef(t, result, "Evaluating %q > %q returned an unexpected result.", test.older, test.newer)
 	}
 }
+
+func TestVersionComparisonEqual(t *testing.T) {
+	for _, version := range []string{"v1.0.0", "v1.0.0-rc1", "v1.0.0-0.20190101120000-abcdef012345"} {
+		assert.Falsef(t, moduleMoreRecentThan(version, version), "Evaluating %q > %q should be false.", version, version)
+	}
+}
+
+func TestSplitMajorPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		base  string
+		major int
+	}{
+		{path: "github.com/foo/bar", base: "github.com/foo/bar", major: 1},
+		{path: "github.com/foo/bar/v2", base: "github.com/foo/bar", major: 2},
+		{path: "github.com/foo/bar/v12", base: "github.com/foo/bar", major: 12},
+		{path: "github.com/foo/bar/v1", base: "github.com/foo/bar/v1", major: 1},
+		{path: "github.com/foo/v2bar", base: "github.com/foo/v2bar", major: 1},
+		{path: "gopkg.in/yaml.v2", base: "gopkg.in/yaml", major: 2},
+		{path: "v2", base: "v2", major: 1},
+	}
+
+	for _, test := range tests {
+		base, major := SplitMajorPath(test.path)
+		assert.Equal(t, test.base, base, "Should strip the major version suffix of %q.", test.path)
+		assert.Equal(t, test.major, major, "Should determine the major version of %q.", test.path)
+	}
+}
@@ -0,0 +1,146 @@
+package depgraph
+
+import (
+	"sort"
+)
+
+// VersionConflicts returns, for each module that is required at different versions by its
+// dependents, the distinct versions that were required. Of these only the highest is selected by
+// Go's minimal version selection. The versions are sorted from oldest to newest and modules that
+// are always required at the same version are not part of the result.
+func (g *DepGraph) VersionConflicts() map[string][]string {
+	conflicts := map[string][]string{}
+	for name, node := range g.nodes {
+		if versions := node.requiredVersions(); len(versions) > 1 {
+			conflicts[name] = versions
+		}
+	}
+	return conflicts
+}
+
+// RequiredVersions returns the distinct versions at which the module with the given path is
+// required by the other modules of the DepGraph, sorted from oldest to newest. The result is empty
+// for the main module and for modules that are not part of the DepGraph.
+func (g *DepGraph) RequiredVersions(path string) []string {
+	node := g.Node(path)
+	if node == nil || node == g.main {
+		return nil
+	}
+	return node.requiredVersions()
+}
+
+// Diamond describes a module that is required at different versions by the modules that depend on
+// it. As all modules share the main module as a common ancestor these dependents form a diamond
+// with it.
+type Diamond struct {
+	// Module that is required at different versions.
+	Leaf *Module
+	// Modules that require Leaf, ordered by path.
+	Intermediaries []*Module
+	// Version of Leaf that is required by each of the intermediaries, keyed by their path.
+	Versions map[string]string
+}
+
+// Diamonds returns a Diamond for each of the modules reported by VersionConflicts, ordered by the
+// path of their leaf. Even though minimal version selection resolves such conflicts, intermediaries
+// that were developed and tested against an older version may behave differently with the selected
+// one.
+func (g *DepGraph) Diamonds() []Diamond {
+	conflicts := g.VersionConflicts()
+
+	var diamonds []Diamond
+	for _, node := range g.sortedNodes() {
+		if _, ok := conflicts[node.Name()]; !ok {
+			continue
+		}
+		diamond := Diamond{Leaf: node.Module, Versions: map[string]string{}}
+		for _, dep := range node.predecessors {
+			if dep.version == "" {
+				continue
+			}
+			diamond.Intermediaries = append(diamond.Intermediaries, g.nodes[dep.begin].Module)
+			diamond.Versions[dep.begin] = dep.version
+		}
+		sort.Slice(diamond.Intermediaries, func(i int, j int) bool {
+			return diamond.Intermediaries[i].Path < diamond.Intermediaries[j].Path
+		})
+		diamonds = append(diamonds, diamond)
+	}
+	return diamonds
+}
+
+// UnstableModules returns the modules whose selected version is a pseudo-version or another kind of
+// pre-release, ordered by path. The main module is never part of the result.
+func (g *DepGraph) UnstableModules() []*Module {
+	var unstable []*Module
+	for _, node := range g.sortedNodes() {
+		if node != g.main && isUnstableVersion(node.SelectedVersion()) {
+			unstable = append(unstable, node.Module)
+		}
+	}
+	return unstable
+}
+
+// MajorVersionHistogram returns the number of modules at each major version, based on their selected
+// version. Pseudo-versions are counted for the major version of the release they are based on. The
+// main module and modules without a valid version are not counted.
+func (g *DepGraph) MajorVersionHistogram() map[int]int {
+	histogram := map[int]int{}
+	for _, node := range g.nodes {
+		if node == g.main {
+			continue
+		}
+		if major, ok := majorVersion(node.SelectedVersion()); ok {
+			histogram[major]++
+		}
+	}
+	return histogram
+}
+
+// IncompatibleMajors returns the modules of the DepGraph that are present at several major
+// versions, keyed by their path without major version suffix. Paths without such a suffix are
+// considered to be at major version 1. The modules for each path are ordered by major version. This
+// is allowed by Go but often results from a replace pointing to a different major version and
+// leads to the same code being built several times.
+func (g *DepGraph) IncompatibleMajors() map[string][]*Module {
+	type majorModule struct {
+		major  int
+		module *Module
+	}
+	byBase := map[string][]majorModule{}
+	for _, node := range g.sortedNodes() {
+		base, major := SplitMajorPath(node.Module.Path)
+		byBase[base] = append(byBase[base], majorModule{major: major, module: node.Module})
+	}
+
+	incompatible := map[string][]*Module{}
+	for base, modules := range byBase {
+		if len(modules) < 2 {
+			continue
+		}
+		sort.SliceStable(modules, func(i int, j int) bool { return modules[i].major < modules[j].major })
+		for _, module := range modules {
+			incompatible[base] = append(incompatible[base], module.module)
+		}
+	}
+	return incompatible
+}
+
+// requiredVersions returns the distinct versions at which the Node is required by its dependents,
+// sorted from oldest to newest.
+func (n *Node) requiredVersions() []string {
+	seen := map[string]struct{}{}
+	var versions []string
+	for _, dep := range n.predecessors {
+		if _, ok := seen[dep.version]; !ok && dep.version != "" {
+			seen[dep.version] = struct{}{}
+			versions = append(versions, dep.version)
+		}
+	}
+	sortVersions(versions)
+	return versions
+}
+
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i int, j int) bool { return moduleMoreRecentThan(versions[j], versions[i]) })
+}
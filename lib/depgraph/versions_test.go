@@ -0,0 +1,89 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// versionedTestGraph returns the test graph where 'moduleA' and 'moduleB' require different
+// versions of 'moduleC'.
+func versionedTestGraph() *DepGraph {
+	graph := testGraph()
+	graph.Node("moduleA").successors[0].version = "v0.1.0"
+	return graph
+}
+
+func Test_VersionConflicts(t *testing.T) {
+	assert.Equal(t, map[string][]string{
+		"moduleC": {"v0.1.0", "v0.2.0"},
+	}, versionedTestGraph().VersionConflicts())
+	assert.Empty(t, testGraph().VersionConflicts(), "Should not report modules required at a single version.")
+}
+
+func Test_RequiredVersions(t *testing.T) {
+	graph := versionedTestGraph()
+	assert.Equal(t, []string{"v0.1.0", "v0.2.0"}, graph.RequiredVersions("moduleC-fork"))
+	assert.Equal(t, []string{"v1.0.0"}, graph.RequiredVersions("moduleA"))
+	assert.Empty(t, graph.RequiredVersions("test/module"), "Should not report versions for the main module.")
+	assert.Empty(t, graph.RequiredVersions("moduleE"))
+}
+
+func Test_IncompatibleMajors(t *testing.T) {
+	assert.Empty(t, testGraph().IncompatibleMajors())
+
+	moduleAv3 := &Module{Path: "moduleA/v3", Version: "v3.0.0"}
+	moduleAv2 := &Module{Path: "moduleA/v2", Version: "v2.1.0"}
+	moduleBv2 := &Module{Path: "moduleB/v2", Version: "v2.0.0", Replace: &Module{Path: "moduleB", Version: "v1.1.0"}}
+	graph := newTestGraph([]*Module{testModuleA, moduleAv3, moduleAv2, moduleBv2, testModuleC}, nil)
+	assert.Equal(t, map[string][]*Module{
+		"moduleA": {testModuleA, moduleAv2, moduleAv3},
+	}, graph.IncompatibleMajors())
+}
+
+func Test_Diamonds(t *testing.T) {
+	assert.Equal(t, []Diamond{
+		{
+			Leaf:           testModuleC,
+			Intermediaries: []*Module{testModuleA, testModuleB},
+			Versions:       map[string]string{"moduleA": "v0.1.0", "moduleB": "v0.2.0"},
+		},
+	}, versionedTestGraph().Diamonds())
+	assert.Empty(t, testGraph().Diamonds(), "Should not report diamonds without version skew.")
+}
+
+func Test_UnstableModules(t *testing.T) {
+	modulePseudo := &Module{Path: "modulePseudo", Version: "v1.9.2-201905291510-0123456789ab"}
+	moduleRC := &Module{Path: "moduleRC", Version: "v0.3.0-rc.1"}
+	moduleIncompatible := &Module{Path: "moduleIncompatible", Version: "v3.0.0+incompatible"}
+	moduleReplaced := &Module{Path: "moduleReplaced", Version: "v1.0.0", Replace: &Module{Path: "moduleFork", Version: "v0.0.0-20190529151000-0123456789ab"}}
+	graph := newTestGraph(
+		[]*Module{testModuleA, modulePseudo, moduleRC, moduleIncompatible, moduleReplaced},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"test/module", "modulePseudo"},
+			{"test/module", "moduleRC"},
+			{"test/module", "moduleIncompatible"},
+			{"test/module", "moduleReplaced"},
+		},
+	)
+	assert.Equal(t, []*Module{modulePseudo, moduleRC, moduleReplaced}, graph.UnstableModules())
+	assert.Empty(t, testGraph().UnstableModules(), "Should not report released versions.")
+}
+
+func Test_MajorVersionHistogram(t *testing.T) {
+	graph := newTestGraph(
+		[]*Module{
+			testModuleA,
+			testModuleB,
+			testModuleC,
+			testModuleD,
+			{Path: "modulePseudo", Version: "v0.0.0-20190529151000-0123456789ab"},
+			{Path: "moduleBasedPseudo", Version: "v3.1.1-0.20190529151000-0123456789ab"},
+			{Path: "moduleIncompatible", Version: "v4.0.0+incompatible"},
+			{Path: "moduleInvalid", Version: "master"},
+		},
+		nil,
+	)
+	assert.Equal(t, map[int]int{0: 2, 1: 2, 2: 1, 3: 1, 4: 1}, graph.MajorVersionHistogram())
+}
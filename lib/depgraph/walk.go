@@ -0,0 +1,21 @@
+package depgraph
+
+// Walk calls the visit function for each module of the DepGraph together with the modules it
+// directly depends on, ordered by path. Modules are visited in the order of TopoSort so that a
+// module's dependencies are visited before the module itself. Cycles do not prevent the traversal:
+// each module is visited exactly once and modules that are part of a cycle are visited in path
+// order once nothing else can be visited. The walk stops at the first error returned by visit which
+// is then returned by Walk.
+func (g *DepGraph) Walk(visit func(m *Module, deps []*Module) error) error {
+	nodes, _ := g.topoOrder()
+	for _, node := range nodes {
+		var deps []*Module
+		for _, dep := range node.sortedSuccessors() {
+			deps = append(deps, g.nodes[dep.end].Module)
+		}
+		if err := visit(node.Module, deps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
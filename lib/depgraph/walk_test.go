@@ -0,0 +1,51 @@
+package depgraph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Walk(t *testing.T) {
+	var visited []*Module
+	dependencies := map[*Module][]*Module{}
+	err := testGraph().Walk(func(m *Module, deps []*Module) error {
+		visited = append(visited, m)
+		dependencies[m] = deps
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleD, testModuleC, testModuleA, testModuleB, testMainModule}, visited)
+	assert.Equal(t, []*Module{testModuleA, testModuleB}, dependencies[testMainModule])
+	assert.Empty(t, dependencies[testModuleD])
+
+	testErr := errors.New("stop")
+	visited = nil
+	err = testGraph().Walk(func(m *Module, _ []*Module) error {
+		visited = append(visited, m)
+		if m == testModuleC {
+			return testErr
+		}
+		return nil
+	})
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, []*Module{testModuleD, testModuleC}, visited, "Should stop at the first error.")
+
+	cyclic := newTestGraph(
+		[]*Module{testModuleA, testModuleB, testModuleD},
+		[][2]string{
+			{"test/module", "moduleA"},
+			{"moduleA", "moduleB"},
+			{"moduleB", "moduleA"},
+			{"moduleB", "moduleD"},
+		},
+	)
+	visited = nil
+	err = cyclic.Walk(func(m *Module, _ []*Module) error {
+		visited = append(visited, m)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*Module{testModuleD, testModuleA, testModuleB, testMainModule}, visited, "Should visit each module once.")
+}
@@ -0,0 +1,96 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// goPackage contains the information that is used from the output of 'go list -deps -json'.
+type goPackage struct {
+	ImportPath string
+	Module     *Module
+	Imports    []string
+}
+
+// AnnotateWeights sets the weight of each dependency in the DepGraph to the number of packages of
+// the depending module that import a package of the required module. The package information is
+// retrieved via 'go list -deps' for all packages of the main module. If it can not be retrieved the
+// DepGraph is left untouched. The 'logger' parameter can be 'nil' in which case the DepGraph's own
+// logger is used.
+func (g *DepGraph) AnnotateWeights(logger *logrus.Logger) error {
+	if logger == nil {
+		logger = g.logger
+	}
+	logger.Debug("Retrieving package information via 'go list'")
+	raw, err := util.RunCommand(logger, true, "go", "list", "-deps", "-json", "./...")
+	if err != nil {
+		logger.Warn("Could not retrieve package information. Continuing without dependency weights.")
+		return nil
+	}
+	packages, err := parsePackageList(bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	g.annotateWeights(packages)
+	return nil
+}
+
+// parsePackageList decodes the stream of JSON objects that is output by 'go list -json'.
+func parsePackageList(r io.Reader) ([]*goPackage, error) {
+	var packages []*goPackage
+	decoder := json.NewDecoder(r)
+	for {
+		pkg := &goPackage{}
+		if err := decoder.Decode(pkg); err == io.EOF {
+			return packages, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve package information from 'go list': %v", err)
+		}
+		packages = append(packages, pkg)
+	}
+}
+
+func (g *DepGraph) annotateWeights(packages []*goPackage) {
+	packageModules := map[string]*Node{}
+	for _, pkg := range packages {
+		if pkg.Module == nil {
+			continue // Packages of the standard library are not part of a module.
+		}
+		if node := g.Node(pkg.Module.Path); node != nil {
+			packageModules[pkg.ImportPath] = node
+		}
+	}
+
+	weights := map[*Dependency]int{}
+	for _, pkg := range packages {
+		begin, ok := packageModules[pkg.ImportPath]
+		if !ok {
+			continue
+		}
+		counted := map[*Dependency]struct{}{}
+		for _, imported := range pkg.Imports {
+			end, ok := packageModules[imported]
+			if !ok || end == begin {
+				continue
+			}
+			for _, dep := range begin.successors {
+				if _, ok = counted[dep]; !ok && dep.end == end.Name() {
+					counted[dep] = struct{}{}
+					weights[dep]++
+				}
+			}
+		}
+	}
+
+	for _, node := range g.nodes {
+		for _, dep := range node.successors {
+			dep.weight = weights[dep]
+		}
+	}
+}
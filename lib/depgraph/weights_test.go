@@ -0,0 +1,54 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AnnotateWeights(t *testing.T) {
+	const goListOutput = `{
+	"ImportPath": "fmt"
+}
+{
+	"ImportPath": "moduleD/pkg",
+	"Module": {"Path": "moduleD"},
+	"Imports": ["fmt"]
+}
+{
+	"ImportPath": "moduleC-fork/pkg",
+	"Module": {"Path": "moduleC", "Replace": {"Path": "moduleC-fork"}},
+	"Imports": ["fmt", "moduleD/pkg"]
+}
+{
+	"ImportPath": "moduleA/foo",
+	"Module": {"Path": "moduleA"},
+	"Imports": ["moduleC-fork/pkg"]
+}
+{
+	"ImportPath": "moduleA/bar",
+	"Module": {"Path": "moduleA"},
+	"Imports": ["moduleA/foo", "moduleC-fork/pkg"]
+}
+{
+	"ImportPath": "test/module",
+	"Module": {"Path": "test/module", "Main": true},
+	"Imports": ["moduleA/bar", "moduleA/foo"]
+}
+`
+	packages, err := parsePackageList(strings.NewReader(goListOutput))
+	require.NoError(t, err)
+
+	graph := testGraph()
+	graph.annotateWeights(packages)
+
+	assert.Equal(t, []Edge{
+		{From: testModuleA, To: testModuleC, Version: "v0.2.0", Weight: 2},
+		{From: testModuleB, To: testModuleC, Version: "v0.2.0", Weight: 0},
+		{From: testModuleC, To: testModuleD, Version: "v2.0.0", Weight: 1},
+		{From: testMainModule, To: testModuleA, Version: "v1.0.0", Weight: 1},
+		{From: testMainModule, To: testModuleB, Version: "v1.1.0", Weight: 0},
+	}, graph.Edges())
+}
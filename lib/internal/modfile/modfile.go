@@ -0,0 +1,145 @@
+package modfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Require represents a 'require' directive.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+	Line     int
+}
+
+// Replace represents a 'replace' directive. The OriginalVersion is empty if all versions of the
+// original module are replaced and the Version is empty for local-path overrides.
+type Replace struct {
+	Original        string
+	OriginalVersion string
+	Override        string
+	Version         string
+	Line            int
+}
+
+// Exclude represents an 'exclude' directive.
+type Exclude struct {
+	Path    string
+	Version string
+	Line    int
+}
+
+// File contains the directives of a go.mod or go.work file that are relevant to gomod, in the order
+// in which they appear in the file.
+type File struct {
+	Module   string
+	Requires []Require
+	Replaces []Replace
+	Excludes []Exclude
+}
+
+// Parse extracts the module, require, replace and exclude directives from the content of a go.mod
+// or go.work file. A leading UTF-8 byte order mark and Windows line endings are accepted so that
+// files authored on any platform are parsed in the same way, and comments are ignored with the
+// exception of the '// indirect' marker of requirements. Invalid directives are skipped and the
+// first of them is reported via the returned error, in which case the returned File still contains
+// all valid directives.
+func Parse(content string) (*File, error) {
+	content = strings.ReplaceAll(strings.TrimPrefix(content, "\ufeff"), "\r\n", "\n")
+
+	file := &File{}
+	var block string
+	var firstErr error
+	for idx, line := range strings.Split(content, "\n") {
+		var comment string
+		if commentIdx := strings.Index(line, "//"); commentIdx >= 0 {
+			line, comment = line[:commentIdx], line[commentIdx+2:]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		directive := block
+		switch {
+		case block != "" && fields[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(fields) == 2 && fields[1] == "(":
+			block = fields[0]
+			continue
+		case block == "":
+			directive, fields = fields[0], fields[1:]
+		}
+		for fieldIdx := range fields {
+			fields[fieldIdx] = unquote(fields[fieldIdx])
+		}
+
+		if err := file.add(directive, fields, strings.TrimSpace(comment), idx+1); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return file, firstErr
+}
+
+func (f *File) add(directive string, fields []string, comment string, line int) error {
+	switch directive {
+	case "module":
+		if len(fields) != 1 {
+			return fmt.Errorf("invalid module directive at line %d", line)
+		}
+		f.Module = fields[0]
+	case "require":
+		if len(fields) != 2 {
+			return fmt.Errorf("invalid require directive at line %d", line)
+		}
+		f.Requires = append(f.Requires, Require{Path: fields[0], Version: fields[1], Indirect: comment == "indirect", Line: line})
+	case "replace":
+		replace, ok := parseReplace(fields)
+		if !ok {
+			return fmt.Errorf("invalid replace directive at line %d", line)
+		}
+		replace.Line = line
+		f.Replaces = append(f.Replaces, replace)
+	case "exclude":
+		if len(fields) != 2 {
+			return fmt.Errorf("invalid exclude directive at line %d", line)
+		}
+		f.Excludes = append(f.Excludes, Exclude{Path: fields[0], Version: fields[1], Line: line})
+	}
+	return nil
+}
+
+func parseReplace(fields []string) (Replace, bool) {
+	var replace Replace
+	switch {
+	case len(fields) >= 3 && fields[1] == "=>":
+		replace.Original, fields = fields[0], fields[2:]
+	case len(fields) >= 4 && fields[2] == "=>":
+		replace.Original, replace.OriginalVersion, fields = fields[0], fields[1], fields[3:]
+	default:
+		return replace, false
+	}
+	switch len(fields) {
+	case 1:
+		replace.Override = fields[0]
+	case 2:
+		replace.Override, replace.Version = fields[0], fields[1]
+	default:
+		return replace, false
+	}
+	return replace, true
+}
+
+// unquote removes the quotes around a path or version that is given as a Go string literal.
+func unquote(field string) string {
+	if !strings.HasPrefix(field, `"`) {
+		return field
+	}
+	if unquoted, err := strconv.Unquote(field); err == nil {
+		return unquoted
+	}
+	return field
+}
@@ -0,0 +1,81 @@
+package modfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	testcases := map[string]struct {
+		input    string
+		expected *File
+		invalid  bool
+	}{
+		"Empty": {
+			input:    "",
+			expected: &File{},
+		},
+		"FullGoMod": {
+			input: `module "github.com/foo/bar"
+
+go 1.12
+
+require moduleA v1.0.0
+require (
+	moduleB v1.1.0 // indirect
+	moduleC v0.2.0 // Not indirect.
+)
+
+// replace moduleA => moduleA-fork v1.0.1
+replace moduleC v0.2.0 => moduleC-fork v0.2.1 // Upstream is broken.
+
+replace (
+	moduleD => ../moduleD
+)
+
+exclude moduleB v1.0.0
+`,
+			expected: &File{
+				Module: "github.com/foo/bar",
+				Requires: []Require{
+					{Path: "moduleA", Version: "v1.0.0", Line: 5},
+					{Path: "moduleB", Version: "v1.1.0", Indirect: true, Line: 7},
+					{Path: "moduleC", Version: "v0.2.0", Line: 8},
+				},
+				Replaces: []Replace{
+					{Original: "moduleC", OriginalVersion: "v0.2.0", Override: "moduleC-fork", Version: "v0.2.1", Line: 12},
+					{Original: "moduleD", Override: "../moduleD", Line: 15},
+				},
+				Excludes: []Exclude{{Path: "moduleB", Version: "v1.0.0", Line: 18}},
+			},
+		},
+		"ByteOrderMarkAndCRLF": {
+			input: "\ufeffmodule moduleA\r\n\r\nreplace (\r\n\tmoduleB => moduleB-fork v1.0.0\r\n)\r\n",
+			expected: &File{
+				Module:   "moduleA",
+				Replaces: []Replace{{Original: "moduleB", Override: "moduleB-fork", Version: "v1.0.0", Line: 4}},
+			},
+		},
+		"Invalid": {
+			input: "module moduleA\n\nrequire moduleB\nreplace moduleC moduleD\nrequire moduleE v1.0.0\n",
+			expected: &File{
+				Module:   "moduleA",
+				Requires: []Require{{Path: "moduleE", Version: "v1.0.0", Line: 5}},
+			},
+			invalid: true,
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			file, err := Parse(tc.input)
+			assert.Equal(t, tc.expected, file)
+			if tc.invalid {
+				assert.EqualError(t, err, "invalid require directive at line 3", "Should report the first invalid directive.")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
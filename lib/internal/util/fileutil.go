@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -36,3 +37,13 @@ func PrepareOutputPath(logger *logrus.Logger, outputPath string, force bool) err
 	}
 	return nil
 }
+
+// IsLocalPath returns whether the override of a replace directive refers to a directory on the
+// local filesystem rather than to a module.
+func IsLocalPath(override string) bool {
+	return strings.HasPrefix(override, "./") ||
+		strings.HasPrefix(override, "../") ||
+		strings.HasPrefix(override, ".\\") ||
+		strings.HasPrefix(override, "..\\") ||
+		filepath.IsAbs(override)
+}
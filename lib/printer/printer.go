@@ -2,9 +2,12 @@ package printer
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -22,22 +25,51 @@ const (
 	FormatPS
 	FormatJPG
 	FormatGIF
+	FormatJSON
+	FormatMermaid
+	FormatGraphML
+	FormatCSV
+	FormatD2
+	FormatHTML
 )
 
 var (
 	FormatToString = map[Format]string{
-		FormatPDF: "pdf",
-		FormatPNG: "png",
-		FormatPS:  "ps",
-		FormatJPG: "jpg",
-		FormatGIF: "gif",
+		FormatPDF:     "pdf",
+		FormatPNG:     "png",
+		FormatPS:      "ps",
+		FormatJPG:     "jpg",
+		FormatGIF:     "gif",
+		FormatJSON:    "json",
+		FormatMermaid: "mermaid",
+		FormatGraphML: "graphml",
+		FormatCSV:     "csv",
+		FormatD2:      "d2",
+		FormatHTML:    "html",
 	}
 	StringToFormat = map[string]Format{
-		"pdf": FormatPDF,
-		"png": FormatPNG,
-		"ps":  FormatPS,
-		"jpg": FormatJPG,
-		"gif": FormatGIF,
+		"pdf":     FormatPDF,
+		"png":     FormatPNG,
+		"ps":      FormatPS,
+		"jpg":     FormatJPG,
+		"gif":     FormatGIF,
+		"json":    FormatJSON,
+		"mermaid": FormatMermaid,
+		"graphml": FormatGraphML,
+		"csv":     FormatCSV,
+		"d2":      FormatD2,
+		"html":    FormatHTML,
+	}
+
+	// textFormats are the formats which are not generated via the 'dot' tool but are instead
+	// directly written out as text.
+	textFormats = map[Format]func(*depgraph.DepGraph, io.Writer) error{
+		FormatJSON:    (*depgraph.DepGraph).WriteJSON,
+		FormatMermaid: (*depgraph.DepGraph).WriteMermaid,
+		FormatGraphML: (*depgraph.DepGraph).WriteGraphML,
+		FormatCSV:     (*depgraph.DepGraph).WriteEdgesCSV,
+		FormatD2:      (*depgraph.DepGraph).WriteD2,
+		FormatHTML:    (*depgraph.DepGraph).WriteHTML,
 	}
 )
 
@@ -53,22 +85,59 @@ type PrintConfig struct {
 	Visual bool
 	// Annotate edges and nodes with their respective versions.
 	Annotate bool
+	// Label the edges whose required version differs from the version that
+	// was selected for the required module, i.e. where minimal version
+	// selection resulted in an upgrade. Has no effect when Annotate is set as
+	// all edges are then labelled.
+	LabelUpgrades bool
+	// Render nodes as records showing the module path and selected version on
+	// separate lines, with the main module in bold. Takes precedence over the
+	// node annotations of Annotate.
+	ShowVersions bool
+	// Render nodes as records like ShowVersions but only show the version of
+	// the modules for which this function returns true. Takes precedence over
+	// ShowVersions when set.
+	ShowVersion func(*depgraph.Module) bool
 	// Force overwriting of pre-existing files at the specified OutputPath.
 	Force bool
 	// Path at which the printed version of the DepGraph should be stored. If
 	// set to a nil-string a temporary file will be created.
 	OutputPath string
-	// OutputFormat to use when writing files with the 'dot' tool.
+	// OutputFormat to use when writing files with the 'dot' tool. When not
+	// printing a visual representation this can be set to a text format such as
+	// JSON, Mermaid, GraphML, CSV, D2 or HTML to use instead of DOT.
 	OutputFormat Format
+	// Group the nodes of the DOT output into clusters based on the first two
+	// elements of their module path, e.g. 'github.com/aws'. Modules with
+	// shorter paths are grouped in a separate cluster.
+	ClusterByOrg bool
+	// Highlight the module with this path as well as all dependency chains
+	// leading to it from the main module. All other nodes and edges are greyed
+	// out in the DOT output.
+	Highlight string
+	// Direction in which the DOT output is laid out: one of 'TB', 'LR', 'BT'
+	// or 'RL'. The default layout of the 'dot' tool is used when empty.
+	RankDir string
+	// Default shape of the nodes of the DOT output, e.g. 'box' or 'ellipse'.
+	// Nodes rendered as records because of ShowVersions or ShowVersion keep
+	// their shape. The default shape of the 'dot' tool is used when empty.
+	NodeShape string
 }
 
+// rankDirs are the values accepted for the RankDir of a PrintConfig.
+var rankDirs = map[string]struct{}{"TB": {}, "LR": {}, "BT": {}, "RL": {}}
+
 // Print takes in a PrintConfig struct and dumps the content of this DepGraph
 // instance according to parameters.
 func Print(graph *depgraph.DepGraph, config *PrintConfig) error {
 	var printer func(*depgraph.DepGraph, *PrintConfig) error
-	if config.Visual {
+	_, isText := textFormats[config.OutputFormat]
+	switch {
+	case config.Visual:
 		printer = PrintToVisual
-	} else {
+	case isText:
+		printer = PrintToText
+	default:
 		printer = PrintToDOT
 	}
 	return printer(graph, config)
@@ -83,6 +152,10 @@ func PrintToVisual(graph *depgraph.DepGraph, config *PrintConfig) error {
 		config.Logger.Debug("Defaulting to PNG output format as none is set and it could not be deduced from the output filename.")
 		config.OutputFormat = FormatPNG
 	}
+	if _, ok := textFormats[config.OutputFormat]; ok {
+		config.Logger.Errorf("The %q format can not be used for a visual representation.", FormatToString[config.OutputFormat])
+		return fmt.Errorf("invalid visual output format %q", FormatToString[config.OutputFormat])
+	}
 
 	tempDir, err := ioutil.TempDir("", "depgraph")
 	if err != nil {
@@ -123,30 +196,32 @@ func PrintToVisual(graph *depgraph.DepGraph, config *PrintConfig) error {
 }
 
 func PrintToDOT(graph *depgraph.DepGraph, config *PrintConfig) error {
-	var err error
-	out := os.Stdout
-	if len(config.OutputPath) > 0 {
-		if err = util.PrepareOutputPath(config.Logger, config.OutputPath, config.Force); err != nil {
-			return err
-		}
+	if _, ok := rankDirs[config.RankDir]; config.RankDir != "" && !ok {
+		config.Logger.Errorf("The %q rank direction is not one of TB, LR, BT or RL.", config.RankDir)
+		return fmt.Errorf("invalid rank direction %q", config.RankDir)
+	}
 
-		out, err = os.OpenFile(config.OutputPath, os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			config.Logger.WithError(err).Errorf("Could not create output file %q.", config.OutputPath)
-			return err
-		}
-		defer func() {
-			_ = out.Close()
-		}()
-		config.Logger.Debugf("Writing DOT graph to %q.", config.OutputPath)
-	} else {
-		config.Logger.Debug("Writing DOT graph to terminal.")
+	out, cleanup, err := openOutput(config, "DOT")
+	if err != nil {
+		return err
 	}
+	defer cleanup()
+
+	highlighted := highlightedNodes(graph, config)
 
 	var fileContent []string
 	fileContent = append(fileContent, "strict digraph {", "  ranksep=3")
+	if config.RankDir != "" {
+		fileContent = append(fileContent, fmt.Sprintf("  rankdir=%s", config.RankDir))
+	}
+	if config.NodeShape != "" {
+		fileContent = append(fileContent, fmt.Sprintf("  node [shape=%q]", config.NodeShape))
+	}
 	for _, node := range graph.Nodes() {
-		fileContent = printNodeToDot(config, node, fileContent)
+		fileContent = printNodeToDot(graph, config, node, highlighted, fileContent)
+	}
+	if config.ClusterByOrg {
+		fileContent = printClustersToDot(graph, fileContent)
 	}
 	fileContent = append(fileContent, "}")
 
@@ -157,9 +232,94 @@ func PrintToDOT(graph *depgraph.DepGraph, config *PrintConfig) error {
 	return nil
 }
 
-func printNodeToDot(config *PrintConfig, node *depgraph.Node, fileContent []string) []string {
+// PrintToText writes the dependency graph in the configured text format (e.g. JSON) to the
+// specified output path or to the terminal if no output path is set.
+func PrintToText(graph *depgraph.DepGraph, config *PrintConfig) error {
+	writer, ok := textFormats[config.OutputFormat]
+	if !ok {
+		return fmt.Errorf("%q is not a text output format", FormatToString[config.OutputFormat])
+	}
+
+	out, cleanup, err := openOutput(config, FormatToString[config.OutputFormat])
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	return writer(graph, out)
+}
+
+func openOutput(config *PrintConfig, kind string) (*os.File, func(), error) {
+	if len(config.OutputPath) == 0 {
+		config.Logger.Debugf("Writing %s graph to terminal.", kind)
+		return os.Stdout, func() {}, nil
+	}
+
+	if err := util.PrepareOutputPath(config.Logger, config.OutputPath, config.Force); err != nil {
+		return nil, nil, err
+	}
+	out, err := os.OpenFile(config.OutputPath, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		config.Logger.WithError(err).Errorf("Could not create output file %q.", config.OutputPath)
+		return nil, nil, err
+	}
+	config.Logger.Debugf("Writing %s graph to %q.", kind, config.OutputPath)
+	return out, func() { _ = out.Close() }, nil
+}
+
+// highlightedNodes returns the set of nodes that are part of a dependency chain leading from the main
+// module to the module that should be highlighted. If no highlighting is requested it returns nil.
+func highlightedNodes(graph *depgraph.DepGraph, config *PrintConfig) map[string]struct{} {
+	if config.Highlight == "" {
+		return nil
+	}
+	target := graph.Node(config.Highlight)
+	if target == nil {
+		config.Logger.Warnf("Module %q to highlight is not part of the dependency graph.", config.Highlight)
+		return nil
+	}
+
+	highlighted := map[string]struct{}{target.Name(): {}}
+	todo := []*depgraph.Node{target}
+	for len(todo) > 0 {
+		for _, dep := range todo[0].Predecessors() {
+			if _, ok := highlighted[dep.Begin()]; !ok {
+				highlighted[dep.Begin()] = struct{}{}
+				todo = append(todo, graph.Node(dep.Begin()))
+			}
+		}
+		todo = todo[1:]
+	}
+	return highlighted
+}
+
+func highlightColour(highlighted map[string]struct{}, names ...string) string {
+	for _, name := range names {
+		if _, ok := highlighted[name]; !ok {
+			return "color=gray"
+		}
+	}
+	return "color=red"
+}
+
+func printNodeToDot(
+	graph *depgraph.DepGraph,
+	config *PrintConfig,
+	node *depgraph.Node,
+	highlighted map[string]struct{},
+	fileContent []string,
+) []string {
 	nodeOptions := []string{}
-	if config.Annotate && len(node.SelectedVersion()) != 0 {
+	if config.ShowVersions || config.ShowVersion != nil {
+		fields := []string{node.Name()}
+		showVersion := config.ShowVersion == nil || config.ShowVersion(node.Module)
+		if showVersion && len(node.SelectedVersion()) != 0 {
+			fields = append(fields, node.SelectedVersion())
+		}
+		nodeOptions = append(nodeOptions, "shape=record", fmt.Sprintf("label=\"{%s}\"", recordLabel(fields...)))
+		if node.Module.Main {
+			nodeOptions = append(nodeOptions, "style=bold")
+		}
+	} else if config.Annotate && len(node.SelectedVersion()) != 0 {
 		var replacement string
 		if node.Module.Replace != nil {
 			replacement = node.Module.Replace.Path + "<br />"
@@ -171,6 +331,14 @@ func printNodeToDot(config *PrintConfig, node *depgraph.Node, fileContent []stri
 			node.SelectedVersion(),
 		))
 	}
+	if node.Module.Indirect {
+		nodeOptions = append(nodeOptions, "style=dashed")
+	}
+	if highlighted != nil {
+		nodeOptions = append(nodeOptions, highlightColour(highlighted, node.Name()))
+	} else if node.Module.Update != nil {
+		nodeOptions = append(nodeOptions, "color=orange")
+	}
 	if len(nodeOptions) > 0 {
 		fileContent = append(fileContent, fmt.Sprintf("  \"%s\" [%s]", node.Name(), strings.Join(nodeOptions, ",")))
 	}
@@ -178,6 +346,18 @@ func printNodeToDot(config *PrintConfig, node *depgraph.Node, fileContent []stri
 		var edgeOptions []string
 		if config.Annotate {
 			edgeOptions = append(edgeOptions, fmt.Sprintf("label=<<font point-size=\"10\">%s</font>>", dep.RequiredVersion()))
+		} else if config.LabelUpgrades && dep.RequiredVersion() != graph.Node(dep.End()).Module.Version {
+			edgeOptions = append(edgeOptions, fmt.Sprintf(
+				"label=<<font point-size=\"10\">%s → %s</font>>",
+				dep.RequiredVersion(),
+				graph.Node(dep.End()).Module.Version,
+			))
+		}
+		if highlighted != nil {
+			edgeOptions = append(edgeOptions, highlightColour(highlighted, dep.Begin(), dep.End()))
+		}
+		if dep.Weight() > 0 {
+			edgeOptions = append(edgeOptions, fmt.Sprintf("penwidth=%.1f", 1+math.Log2(float64(dep.Weight()))))
 		}
 		fileContent = append(fileContent, fmt.Sprintf(
 			"  \"%s\" -> \"%s\"%s",
@@ -188,3 +368,63 @@ func printNodeToDot(config *PrintConfig, node *depgraph.Node, fileContent []stri
 	}
 	return fileContent
 }
+
+// defaultCluster is the label of the cluster that groups all modules whose path is too short to
+// determine an organisation from.
+const defaultCluster = "other"
+
+func printClustersToDot(graph *depgraph.DepGraph, fileContent []string) []string {
+	clusters := map[string][]string{}
+	for name := range graph.Nodes() {
+		org := moduleOrg(name)
+		clusters[org] = append(clusters[org], name)
+	}
+	orgs := make([]string, 0, len(clusters))
+	for org := range clusters {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+
+	for idx, org := range orgs {
+		sort.Strings(clusters[org])
+		fileContent = append(fileContent, fmt.Sprintf("  subgraph cluster_%d {", idx), fmt.Sprintf("    label=\"%s\"", labelEscaper.Replace(org)))
+		for _, name := range clusters[org] {
+			fileContent = append(fileContent, fmt.Sprintf("    \"%s\"", name))
+		}
+		fileContent = append(fileContent, "  }")
+	}
+	return fileContent
+}
+
+// moduleOrg returns the first two elements of the module path, or the default cluster if the path
+// does not have more than two elements.
+func moduleOrg(path string) string {
+	elements := strings.SplitN(path, "/", 3)
+	if len(elements) < 3 {
+		return defaultCluster
+	}
+	return elements[0] + "/" + elements[1]
+}
+
+// labelEscaper escapes the characters that are special within a quoted DOT string.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+var recordEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+// recordLabel returns the label of a DOT record node with the given fields stacked on top of each
+// other.
+func recordLabel(fields ...string) string {
+	escaped := make([]string, 0, len(fields))
+	for _, field := range fields {
+		escaped = append(escaped, recordEscaper.Replace(field))
+	}
+	return strings.Join(escaped, "|")
+}
@@ -0,0 +1,115 @@
+package reveal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+// WriteBaseline writes the replacements to the specified writer in a format that can be read back
+// by CompareBaseline. Each replacement is written on a separate line as the tab-separated offender,
+// original, original version, override and version. Deduplicated replacements are written once for
+// each of their offenders. Lines are sorted so that the output is stable
+// and can be committed and diffed.
+func (r *Replacements) WriteBaseline(w io.Writer) error {
+	var lines []string
+	for _, original := range r.replacedModules {
+		for _, replacement := range r.originToReplace[original] {
+			for _, offender := range baselineEntries(replacement) {
+				lines = append(lines, baselineLine(offender))
+			}
+		}
+	}
+	sort.Strings(lines)
+
+	var output strings.Builder
+	for _, line := range lines {
+		output.WriteString(line + "\n")
+	}
+	if _, err := io.WriteString(w, output.String()); err != nil {
+		return fmt.Errorf("failed to write baseline: %v", err)
+	}
+	return nil
+}
+
+// CompareBaseline reads a baseline that was written by WriteBaseline and compares it with the
+// current replacements. It returns the replacements that were added since the baseline was written
+// and the ones that were removed, both ordered in the same way as the baseline. As the baseline only
+// records the path of each offender the removed replacements only carry that information.
+func CompareBaseline(r io.Reader, current *Replacements) ([]Replacement, []Replacement, error) {
+	baseline := map[string]Replacement{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 5 {
+			return nil, nil, fmt.Errorf("invalid baseline entry at line %d: %q", lineNumber, line)
+		}
+		baseline[line] = Replacement{
+			Offender:        &depgraph.Module{Path: fields[0]},
+			Original:        fields[1],
+			OriginalVersion: fields[2],
+			Override:        fields[3],
+			Version:         fields[4],
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read baseline: %v", err)
+	}
+
+	seen := map[string]Replacement{}
+	for _, original := range current.replacedModules {
+		for _, replacement := range current.originToReplace[original] {
+			for _, offender := range baselineEntries(replacement) {
+				seen[baselineLine(offender)] = offender
+			}
+		}
+	}
+	return baselineDifference(seen, baseline), baselineDifference(baseline, seen), nil
+}
+
+// baselineEntries splits the given replacement into one replacement for each of its offenders.
+func baselineEntries(replacement Replacement) []Replacement {
+	var entries []Replacement
+	for _, offender := range replacementOffenders(replacement) {
+		entry := replacement
+		entry.Offender, entry.Offenders = offender, nil
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func baselineLine(replacement Replacement) string {
+	return strings.Join([]string{
+		replacement.Offender.Path,
+		replacement.Original,
+		replacement.OriginalVersion,
+		replacement.Override,
+		replacement.Version,
+	}, "\t")
+}
+
+// baselineDifference returns the replacements of lhs that are not part of rhs, ordered by their
+// baseline line.
+func baselineDifference(lhs map[string]Replacement, rhs map[string]Replacement) []Replacement {
+	var lines []string
+	for line := range lhs {
+		if _, ok := rhs[line]; !ok {
+			lines = append(lines, line)
+		}
+	}
+	sort.Strings(lines)
+
+	var difference []Replacement
+	for _, line := range lines {
+		difference = append(difference, lhs[line])
+	}
+	return difference
+}
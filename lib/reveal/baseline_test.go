@@ -0,0 +1,81 @@
+package reveal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_WriteBaseline(t *testing.T) {
+	const expectedOutput = "moduleA\toriginalB\t\toverrideB\tv1.0.0\n" +
+		"moduleA\toriginalC\t\t./overrideC\t\n" +
+		"offender\toriginalA\t\toverrideA\tv1.0.0\n" +
+		"offender-bis\toriginalA\t\toverrideA-bis\tv2.0.0\n" +
+		"offender-tertio\toriginalB\t\toverrideB-bis\tv2.0.0\n"
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testReplacements.WriteBaseline(writer))
+	assert.Equal(t, expectedOutput, writer.String(), "Should write the expected baseline.")
+
+	offender, offenderBis := &depgraph.Module{Path: "offender"}, &depgraph.Module{Path: "offender-bis"}
+	deduplicated := &Replacements{
+		replacedModules: []string{"originalA"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {{Offender: offender, Offenders: []*depgraph.Module{offender, offenderBis}, Original: "originalA", Override: "overrideA"}},
+		},
+	}
+	writer = &strings.Builder{}
+	assert.NoError(t, deduplicated.WriteBaseline(writer))
+	assert.Equal(
+		t,
+		"offender\toriginalA\t\toverrideA\t\noffender-bis\toriginalA\t\toverrideA\t\n",
+		writer.String(),
+		"Should write every offender of deduplicated replacements.",
+	)
+}
+
+func Test_CompareBaseline(t *testing.T) {
+	writer := &strings.Builder{}
+	require.NoError(t, testReplacements.WriteBaseline(writer))
+
+	added, removed, err := CompareBaseline(strings.NewReader(writer.String()), testReplacements)
+	require.NoError(t, err)
+	assert.Empty(t, added, "Should not report changes against an identical baseline.")
+	assert.Empty(t, removed, "Should not report changes against an identical baseline.")
+
+	current := &Replacements{
+		replacedModules: []string{"originalB", "originalC", "originalD"},
+		originToReplace: map[string][]Replacement{
+			"originalB": {replaceB},
+			"originalC": {replaceC},
+			"originalD": {replaceH},
+		},
+	}
+	added, removed, err = CompareBaseline(strings.NewReader(writer.String()), current)
+	require.NoError(t, err)
+	assert.Equal(t, []Replacement{replaceH}, added)
+	assert.Equal(t, []Replacement{
+		{Offender: &depgraph.Module{Path: "offender"}, Original: "originalA", Override: "overrideA", Version: "v1.0.0"},
+		{Offender: &depgraph.Module{Path: "offender-bis"}, Original: "originalA", Override: "overrideA-bis", Version: "v2.0.0"},
+		{Offender: &depgraph.Module{Path: "offender-tertio"}, Original: "originalB", Override: "overrideB-bis", Version: "v2.0.0"},
+	}, removed)
+
+	offender, offenderBis := &depgraph.Module{Path: "offender"}, &depgraph.Module{Path: "offender-bis"}
+	deduplicated := &Replacements{
+		replacedModules: []string{"originalA"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {{Offender: offender, Offenders: []*depgraph.Module{offender, offenderBis}, Original: "originalA", Override: "overrideA"}},
+		},
+	}
+	added, removed, err = CompareBaseline(strings.NewReader("offender\toriginalA\t\toverrideA\t\n"), deduplicated)
+	require.NoError(t, err)
+	assert.Equal(t, []Replacement{{Offender: offenderBis, Original: "originalA", Override: "overrideA"}}, added, "Should compare each offender.")
+	assert.Empty(t, removed)
+
+	_, _, err = CompareBaseline(strings.NewReader("not a baseline\n"), current)
+	assert.Error(t, err, "Should reject malformed baselines.")
+}
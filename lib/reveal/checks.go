@@ -0,0 +1,496 @@
+package reveal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// Conflicts returns, for each replaced module, all of its replacements if they do not agree on the
+// override and version that should be used instead of it. Local-path overrides agree if they resolve
+// to the same directory, see LocalPathConflicts for the directories involved.
+func (r *Replacements) Conflicts() map[string][]Replacement {
+	conflicts := map[string][]Replacement{}
+	for _, original := range r.replacedModules {
+		if r.hasConflict(original) {
+			conflicts[original] = append([]Replacement(nil), r.originToReplace[original]...)
+		}
+	}
+	return conflicts
+}
+
+// hasConflict returns whether the replacements of the specified module disagree on the override or
+// version to use. Local-path overrides are compared based on the directory they resolve to.
+func (r *Replacements) hasConflict(original string) bool {
+	replaces := r.originToReplace[original]
+	for _, replace := range replaces {
+		if overrideTarget(replace) != overrideTarget(replaces[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideTarget returns a representation of the code that the replacement's override refers to.
+func overrideTarget(replace Replacement) string {
+	if util.IsLocalPath(replace.Override) {
+		return localOverrideDir("", replace)
+	}
+	return replace.Override + "@" + replace.Version
+}
+
+// LocalPathConflicts returns, for each replaced module for which local-path overrides resolve to
+// different directories, these replacements grouped by the absolute directory they resolve to.
+// Relative overrides are resolved in the same way as by ValidateLocalPaths.
+func (r *Replacements) LocalPathConflicts(baseDir string) map[string]map[string][]Replacement {
+	conflicts := map[string]map[string][]Replacement{}
+	for _, original := range r.replacedModules {
+		byDir := map[string][]Replacement{}
+		for _, replace := range r.originToReplace[original] {
+			if !util.IsLocalPath(replace.Override) {
+				continue
+			}
+			dir := localOverrideDir(baseDir, replace)
+			if absDir, err := filepath.Abs(dir); err == nil {
+				dir = absDir
+			}
+			byDir[dir] = append(byDir[dir], replace)
+		}
+		if len(byDir) > 1 {
+			conflicts[original] = byDir
+		}
+	}
+	return conflicts
+}
+
+// Effective returns, for each module that is replaced by the main module's go.mod, the replacement
+// that is actually applied when building the main module. Replace directives of any other module are
+// ignored by the Go tooling and therefore never take effect.
+func (r *Replacements) Effective() map[string]Replacement {
+	effective := make(map[string]Replacement, len(r.topLevel))
+	for original, replace := range r.topLevel {
+		effective[original] = replace
+	}
+	return effective
+}
+
+// DuplicateReplaces returns, for each offender whose go.mod replaces the same module more than once,
+// the sorted list of these modules. A module replaced at a specific version is reported as
+// 'module@version'. Such go.mod files are rejected by 'go' and have most likely been edited by hand.
+// The go.mod and go.work files of the main module are checked as well, the latter being reported
+// as part of the main module.
+func (r *Replacements) DuplicateReplaces() map[string][]string {
+	duplicates := make(map[string][]string, len(r.duplicates))
+	for offender, originals := range r.duplicates {
+		duplicates[offender] = append([]string(nil), originals...)
+	}
+	return duplicates
+}
+
+func containsString(list []string, value string) bool {
+	for _, entry := range list {
+		if entry == value {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmatchedTopLevel returns the replacements of modules that are not replaced by the main module's
+// go.mod. These potentially need to be taken into account at the top-level in order to build the
+// main module as its dependencies intended to.
+func (r *Replacements) UnmatchedTopLevel() []Replacement {
+	var unmatched []Replacement
+	for _, original := range r.replacedModules {
+		if _, ok := r.topLevel[original]; !ok {
+			unmatched = append(unmatched, r.originToReplace[original]...)
+		}
+	}
+	return unmatched
+}
+
+// StaleAgainst returns the replacements that are likely no longer necessary because the override
+// version is not more recent than the version of the replaced module that is selected in the
+// given dependency graph. Replacements with a local-path override, by a different module or of
+// modules that are not part of the graph are ignored as their versions can not be compared.
+func (r *Replacements) StaleAgainst(graph *depgraph.DepGraph) []Replacement {
+	var stale []Replacement
+	for _, original := range r.replacedModules {
+		node := graph.Node(original)
+		if node == nil {
+			continue
+		}
+		for _, replace := range r.originToReplace[original] {
+			if replace.Version == "" || replace.Override != replace.Original {
+				continue
+			}
+			if !depgraph.VersionMoreRecentThan(replace.Version, node.Module.Version) {
+				stale = append(stale, replace)
+			}
+		}
+	}
+	return stale
+}
+
+// Downgrades returns the replacements that substitute a module by an older version of itself than
+// the one that is selected in the given dependency graph. Replacements with a local-path override,
+// by a different module or of modules that are not part of the graph are ignored as their versions
+// can not be compared.
+func (r *Replacements) Downgrades(graph *depgraph.DepGraph) []Replacement {
+	var downgrades []Replacement
+	for _, original := range r.replacedModules {
+		node := graph.Node(original)
+		if node == nil {
+			continue
+		}
+		for _, replace := range r.originToReplace[original] {
+			if replace.Version == "" || replace.Override != replace.Original {
+				continue
+			}
+			if depgraph.VersionMoreRecentThan(node.Module.Version, replace.Version) {
+				downgrades = append(downgrades, replace)
+			}
+		}
+	}
+	return downgrades
+}
+
+// OrphanedReplaces returns the replacements of modules that are not part of the given dependency
+// graph. Such replace directives have no effect on the build and can be removed. Replacements of
+// the graph's main module are never considered to be orphaned.
+func (r *Replacements) OrphanedReplaces(graph *depgraph.DepGraph) []Replacement {
+	var orphaned []Replacement
+	for _, original := range r.replacedModules {
+		if original == graph.Main().Name() || graph.Node(original) != nil {
+			continue
+		}
+		orphaned = append(orphaned, r.originToReplace[original]...)
+	}
+	return orphaned
+}
+
+// MajorVersionMismatches returns the replacements for which the major version suffix of the
+// override's module path, such as '/v2', differs from that of the original's module path. Paths
+// without such a suffix are considered to be at major version 1. Replacements with a local-path
+// override are ignored.
+func (r *Replacements) MajorVersionMismatches() []Replacement {
+	var mismatches []Replacement
+	for _, original := range r.replacedModules {
+		for _, replace := range r.originToReplace[original] {
+			if util.IsLocalPath(replace.Override) {
+				continue
+			}
+			_, originalMajor := depgraph.SplitMajorPath(replace.Original)
+			_, overrideMajor := depgraph.SplitMajorPath(replace.Override)
+			if originalMajor != overrideMajor {
+				mismatches = append(mismatches, replace)
+			}
+		}
+	}
+	return mismatches
+}
+
+// NoOpReplaces returns the replacements that substitute a module by the exact same module path and
+// version, which has no effect and can be removed. The top-level replaces of the main module and
+// its workspace are checked first, followed by those of dependencies. Replacements with a
+// local-path override are never considered to be no-ops, even if the path is identical to that of
+// the original.
+func (r *Replacements) NoOpReplaces() []Replacement {
+	originals := make([]string, 0, len(r.topLevel))
+	for original := range r.topLevel {
+		originals = append(originals, original)
+	}
+	sort.Strings(originals)
+
+	candidates := make([]Replacement, 0, len(originals))
+	for _, original := range originals {
+		candidates = append(candidates, r.topLevel[original])
+	}
+	for _, original := range r.replacedModules {
+		candidates = append(candidates, r.originToReplace[original]...)
+	}
+
+	var noOps []Replacement
+	for _, replace := range candidates {
+		if util.IsLocalPath(replace.Override) {
+			continue
+		}
+		if replace.Original == replace.Override && replace.OriginalVersion == replace.Version {
+			noOps = append(noOps, replace)
+		}
+	}
+	return noOps
+}
+
+// ConsolidationSuggestions returns a replacement for each replaced module for which all offenders
+// agree on the override and version to use as well as on the version of the module that they
+// replace, but for which the main module does not yet declare a replace directive itself. These are
+// good candidates for being hoisted into the main module's go.mod.
+func (r *Replacements) ConsolidationSuggestions() []Replacement {
+	conflicts := r.Conflicts()
+
+	var suggestions []Replacement
+	for _, original := range r.replacedModules {
+		if _, ok := r.topLevel[original]; ok {
+			continue
+		}
+		if _, ok := conflicts[original]; ok {
+			continue
+		}
+		replaces := r.originToReplace[original]
+		if !sameOriginalVersion(replaces) {
+			continue
+		}
+		suggestions = append(suggestions, replaces[0])
+	}
+	return suggestions
+}
+
+// sameOriginalVersion returns whether the replacements are all restricted to the same version of
+// the module they replace, or none of them is.
+func sameOriginalVersion(replaces []Replacement) bool {
+	for _, replace := range replaces {
+		if replace.OriginalVersion != replaces[0].OriginalVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// TopLevelImpact returns, for each module that is replaced in the main module's go.mod, the number
+// of modules of the graph that depend on it either directly or transitively, including the main
+// module. Modules that are not part of the graph have an impact of zero which indicates that their
+// replace can be removed.
+func (r *Replacements) TopLevelImpact(graph *depgraph.DepGraph) map[string]int {
+	impact := make(map[string]int, len(r.topLevel))
+	for original := range r.topLevel {
+		if graph.Node(original) == nil {
+			impact[original] = 0
+			continue
+		}
+		dependents, err := graph.Dependents(original)
+		if err != nil {
+			continue
+		}
+		impact[original] = len(dependents)
+	}
+	return impact
+}
+
+// TopLevelSnippet writes a replace block for the main module's go.mod to the specified writer. It
+// contains the replacements returned by ConsolidationSuggestions in the format used by 'go mod
+// edit'. Nothing is written if there are no replacements to hoist.
+func (r *Replacements) TopLevelSnippet(w io.Writer) error {
+	suggestions := r.ConsolidationSuggestions()
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	lines := []string{"replace ("}
+	for _, replace := range suggestions {
+		original, override := replace.Original, replace.Override
+		if replace.OriginalVersion != "" {
+			original += " " + replace.OriginalVersion
+		}
+		if replace.Version != "" {
+			override += " " + replace.Version
+		}
+		lines = append(lines, fmt.Sprintf("\t%s => %s", original, override))
+	}
+	lines = append(lines, ")")
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		return fmt.Errorf("failed to write go.mod snippet: %v", err)
+	}
+	return nil
+}
+
+// ValidateLocalPaths checks that each replacement with a local-path override points to a directory
+// containing a go.mod file. Relative overrides are resolved against the directory of the offending
+// module, which itself is resolved against baseDir if it is not absolute. An error is returned for
+// each override that does not refer to a valid module directory.
+func (r *Replacements) ValidateLocalPaths(baseDir string) []error {
+	var errs []error
+	for _, original := range r.replacedModules {
+		for _, replace := range r.originToReplace[original] {
+			if !util.IsLocalPath(replace.Override) {
+				continue
+			}
+			overrideDir := localOverrideDir(baseDir, replace)
+			if _, err := os.Stat(filepath.Join(overrideDir, "go.mod")); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"%s replaces %q with %q but %q does not contain a go.mod file",
+					replace.Offender.Path,
+					replace.Original,
+					replace.Override,
+					overrideDir,
+				))
+			}
+		}
+	}
+	return errs
+}
+
+// ValidateOverrideVersions checks that the version of each replacement's module override can be
+// resolved via 'go list -m', which may require network access. An error is returned for each
+// override that can not be resolved. Local-path overrides are not checked. The 'logger' parameter
+// can be 'nil' which will result in no output or logging information to be provided.
+func (r *Replacements) ValidateOverrideVersions(logger *logrus.Logger) []error {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+	return r.validateOverrideVersions(func(module string, version string) error {
+		_, err := util.RunCommand(logger, true, "go", "list", "-m", "-json", module+"@"+version)
+		return err
+	})
+}
+
+func (r *Replacements) validateOverrideVersions(resolve func(module string, version string) error) []error {
+	resolved := map[string]error{}
+
+	var errs []error
+	for _, original := range r.replacedModules {
+		for _, replace := range r.originToReplace[original] {
+			if util.IsLocalPath(replace.Override) || replace.Version == "" {
+				continue
+			}
+			key := replace.Override + "@" + replace.Version
+			err, ok := resolved[key]
+			if !ok {
+				err = resolve(replace.Override, replace.Version)
+				resolved[key] = err
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf(
+					"%s replaces %q with %q but version %s can not be resolved: %v",
+					replace.Offender.Path,
+					replace.Original,
+					replace.Override,
+					replace.Version,
+					err,
+				))
+			}
+		}
+	}
+	return errs
+}
+
+// SimplifiableVersion is a replacement reported by SimplifiableVersions together with the latest
+// version that is available for its override.
+type SimplifiableVersion struct {
+	Replacement Replacement
+	Latest      string
+	// Outdated is set when the override is pinned at a version older than Latest. Otherwise it is
+	// pinned at the latest version itself.
+	Outdated bool
+}
+
+// SimplifiableVersions returns the replacements of dependencies whose module override is pinned at
+// the latest version that is available for it or at an older one. As 'go' requires a version for
+// any override that is not a local path such a pin can not simply be dropped, but one at the latest
+// version will silently fall behind with the next release while an older one often indicates a pin
+// that was forgotten. The latest versions are determined via 'go list -m', which may require
+// network access. Overrides for which this fails are skipped. Local path overrides and the
+// replacements of the main module are not checked. The 'logger' parameter can be 'nil' which will
+// result in no output or logging information to be provided.
+func (r *Replacements) SimplifiableVersions(logger *logrus.Logger) []SimplifiableVersion {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+	return r.simplifiableVersions(logger, func(module string) (string, error) {
+		raw, err := util.RunCommand(logger, true, "go", "list", "-m", "-json", module+"@latest")
+		if err != nil {
+			return "", err
+		}
+		var latest struct{ Version string }
+		if err = json.Unmarshal(raw, &latest); err != nil {
+			return "", fmt.Errorf("unable to parse 'go list -m' output for %q: %v", module, err)
+		}
+		return latest.Version, nil
+	})
+}
+
+func (r *Replacements) simplifiableVersions(logger *logrus.Logger, latest func(module string) (string, error)) []SimplifiableVersion {
+	latestVersions := map[string]string{}
+
+	var simplifiable []SimplifiableVersion
+	for _, original := range r.replacedModules {
+		for _, replace := range r.originToReplace[original] {
+			if util.IsLocalPath(replace.Override) || replace.Version == "" || replace.Offender.Path == r.main {
+				continue
+			}
+			version, ok := latestVersions[replace.Override]
+			if !ok {
+				var err error
+				if version, err = latest(replace.Override); err != nil {
+					logger.WithError(err).Warnf("Could not determine the latest version of %q.", replace.Override)
+				}
+				latestVersions[replace.Override] = version
+			}
+			if version != "" && !depgraph.VersionMoreRecentThan(replace.Version, version) {
+				simplifiable = append(simplifiable, SimplifiableVersion{
+					Replacement: replace,
+					Latest:      version,
+					Outdated:    depgraph.VersionMoreRecentThan(version, replace.Version),
+				})
+			}
+		}
+	}
+	return simplifiable
+}
+
+// ExternalLocalReplaces returns the replacements with a local-path override that refers to a
+// directory outside of repoRoot. Overrides are resolved in the same way as by ValidateLocalPaths
+// with repoRoot as the base directory. Such replacements only work on machines that share the same
+// directory layout outside of the repository.
+func (r *Replacements) ExternalLocalReplaces(repoRoot string) []Replacement {
+	root, err := filepath.Abs(repoRoot)
+	if err != nil {
+		root = filepath.Clean(repoRoot)
+	}
+
+	var external []Replacement
+	for _, original := range r.replacedModules {
+		for _, replace := range r.originToReplace[original] {
+			if !util.IsLocalPath(replace.Override) {
+				continue
+			}
+			overrideDir, err := filepath.Abs(localOverrideDir(root, replace))
+			if err != nil {
+				continue
+			}
+			if rel, err := filepath.Rel(root, overrideDir); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				external = append(external, replace)
+			}
+		}
+	}
+	return external
+}
+
+// localOverrideDir resolves the directory to which the local-path override of the replacement
+// refers.
+func localOverrideDir(baseDir string, replace Replacement) string {
+	if filepath.IsAbs(replace.Override) {
+		return filepath.Clean(replace.Override)
+	}
+	offenderDir := replace.Offender.Path
+	if replace.Offender.GoMod != "" {
+		offenderDir = filepath.Dir(replace.Offender.GoMod)
+	}
+	if !filepath.IsAbs(offenderDir) {
+		offenderDir = filepath.Join(baseDir, offenderDir)
+	}
+	return filepath.Join(offenderDir, filepath.FromSlash(replace.Override))
+}
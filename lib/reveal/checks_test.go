@@ -0,0 +1,396 @@
+package reveal
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_Conflicts(t *testing.T) {
+	assert.Equal(t, map[string][]Replacement{
+		"originalA": {replaceA, replaceE},
+		"originalB": {replaceB, replaceF},
+	}, testReplacements.Conflicts())
+
+	agreeing := &Replacements{
+		replacedModules: []string{"originalA"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, {Offender: replaceE.Offender, Original: "originalA", Override: "overrideA", Version: "v1.0.0"}},
+		},
+	}
+	assert.Empty(t, agreeing.Conflicts(), "Should not report agreeing replacements.")
+}
+
+func Test_Effective(t *testing.T) {
+	mainModule := &depgraph.Module{Main: true, Path: "test-module"}
+	topLevel := Replacement{Offender: mainModule, Original: "originalA", OriginalVersion: "v0.9.0", Override: "overrideA", Version: "v1.0.0", Line: 5}
+	replacements := &Replacements{main: "test-module", topLevel: map[string]Replacement{"originalA": topLevel}}
+	assert.Equal(t, map[string]Replacement{"originalA": topLevel}, replacements.Effective(), "Should return the top-level replaces as parsed.")
+
+	assert.Empty(t, (&Replacements{main: "test-module"}).Effective(), "Should not report replaces without top-level ones.")
+}
+
+func Test_UnmatchedTopLevel(t *testing.T) {
+	assert.Equal(t, []Replacement{replaceC}, testReplacements.UnmatchedTopLevel())
+}
+
+func Test_StaleAgainst(t *testing.T) {
+	graph := depgraph.NewGraph(nil, &depgraph.Module{Main: true, Path: "test-module"})
+	graph.AddNode(&depgraph.Module{Path: "originalA", Version: "v1.5.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalB", Version: "v1.0.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalC", Version: "v1.0.0"})
+
+	assert.Empty(t, testReplacements.StaleAgainst(graph), "Should not compare the versions of different modules.")
+
+	pinA := Replacement{Offender: moduleA, Original: "originalA", Override: "originalA", Version: "v1.5.0"}
+	upgradeA := Replacement{Offender: moduleB, Original: "originalA", Override: "originalA", Version: "v1.6.0"}
+	pinB := Replacement{Offender: moduleA, Original: "originalB", Override: "originalB", Version: "v0.9.0"}
+	forkB := Replacement{Offender: moduleB, Original: "originalB", Override: "overrideB", Version: "v0.1.0"}
+	replacements := &Replacements{
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {pinA, upgradeA},
+			"originalB": {pinB, forkB},
+			"originalC": {replaceC},
+		},
+	}
+	assert.Equal(t, []Replacement{pinA, pinB}, replacements.StaleAgainst(graph))
+}
+
+func Test_Downgrades(t *testing.T) {
+	graph := depgraph.NewGraph(nil, &depgraph.Module{Main: true, Path: "test-module"})
+	graph.AddNode(&depgraph.Module{Path: "originalA", Version: "v1.5.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalB", Version: "v1.5.0"})
+
+	downgradeA := Replacement{Offender: moduleA, Original: "originalA", Override: "originalA", Version: "v1.2.0"}
+	upgradeA := Replacement{Offender: moduleB, Original: "originalA", Override: "originalA", Version: "v1.6.0"}
+	forkB := Replacement{Offender: moduleA, Original: "originalB", Override: "overrideB", Version: "v1.0.0"}
+	unknownC := Replacement{Offender: moduleA, Original: "originalC", Override: "originalC", Version: "v0.1.0"}
+	replacements := &Replacements{
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {downgradeA, upgradeA},
+			"originalB": {forkB},
+			"originalC": {unknownC},
+		},
+	}
+	assert.Equal(t, []Replacement{downgradeA}, replacements.Downgrades(graph))
+}
+
+func Test_OrphanedReplaces(t *testing.T) {
+	graph := depgraph.NewGraph(nil, &depgraph.Module{Main: true, Path: "test-module"})
+	graph.AddNode(&depgraph.Module{Path: "originalA", Version: "v1.5.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalB", Version: "v1.0.0"})
+
+	assert.Equal(t, []Replacement{replaceC}, testReplacements.OrphanedReplaces(graph))
+
+	replaceMain := Replacement{Offender: moduleA, Original: "test-module", Override: "../test-module"}
+	replacements := &Replacements{
+		replacedModules: []string{"test-module"},
+		originToReplace: map[string][]Replacement{"test-module": {replaceMain}},
+	}
+	assert.Empty(t, replacements.OrphanedReplaces(graph), "Should never consider the main module as orphaned.")
+}
+
+func Test_MajorVersionMismatches(t *testing.T) {
+	assert.Empty(t, testReplacements.MajorVersionMismatches(), "Should not report replacements without major version suffixes.")
+
+	toV2 := Replacement{Offender: moduleA, Original: "github.com/foo/bar", Override: "github.com/foo/bar/v2", Version: "v2.0.0"}
+	toV1 := Replacement{Offender: moduleA, Original: "github.com/foo/baz/v3", Override: "github.com/fork/baz", Version: "v1.0.0"}
+	sameMajor := Replacement{Offender: moduleA, Original: "github.com/foo/qux/v2", Override: "github.com/fork/qux/v2", Version: "v2.1.0"}
+	gopkgIn := Replacement{Offender: moduleA, Original: "gopkg.in/yaml.v2", Override: "gopkg.in/yaml.v3", Version: "v3.0.0"}
+	local := Replacement{Offender: moduleA, Original: "github.com/foo/quux/v2", Override: "../quux"}
+	replacements := &Replacements{
+		replacedModules: []string{"github.com/foo/bar", "github.com/foo/baz/v3", "github.com/foo/quux/v2", "github.com/foo/qux/v2", "gopkg.in/yaml.v2"},
+		originToReplace: map[string][]Replacement{
+			"github.com/foo/bar":     {toV2},
+			"github.com/foo/baz/v3":  {toV1},
+			"github.com/foo/quux/v2": {local},
+			"github.com/foo/qux/v2":  {sameMajor},
+			"gopkg.in/yaml.v2":       {gopkgIn},
+		},
+	}
+	assert.Equal(t, []Replacement{toV2, toV1, gopkgIn}, replacements.MajorVersionMismatches())
+}
+
+func Test_NoOpReplaces(t *testing.T) {
+	assert.Empty(t, testReplacements.NoOpReplaces())
+
+	noOp := Replacement{Offender: moduleA, Original: "github.com/foo/bar", OriginalVersion: "v1.2.3", Override: "github.com/foo/bar", Version: "v1.2.3"}
+	pinned := Replacement{Offender: moduleA, Original: "github.com/foo/baz", Override: "github.com/foo/baz", Version: "v1.2.3"}
+	otherVersion := Replacement{Offender: moduleA, Original: "github.com/foo/qux", OriginalVersion: "v1.0.0", Override: "github.com/foo/qux", Version: "v1.2.3"}
+	local := Replacement{Offender: moduleA, Original: "../quux", Override: "../quux"}
+	replacements := &Replacements{
+		replacedModules: []string{"../quux", "github.com/foo/bar", "github.com/foo/baz", "github.com/foo/qux"},
+		originToReplace: map[string][]Replacement{
+			"../quux":            {local},
+			"github.com/foo/bar": {noOp},
+			"github.com/foo/baz": {pinned},
+			"github.com/foo/qux": {otherVersion},
+		},
+	}
+	assert.Equal(t, []Replacement{noOp}, replacements.NoOpReplaces())
+
+	topLevelNoOp := Replacement{Offender: moduleA, Original: "github.com/foo/corge", Override: "github.com/foo/corge"}
+	replacements.topLevel = map[string]Replacement{
+		"../quux":              local,
+		"github.com/foo/baz":   pinned,
+		"github.com/foo/corge": topLevelNoOp,
+	}
+	assert.Equal(t, []Replacement{topLevelNoOp, noOp}, replacements.NoOpReplaces(), "Should also consider the top-level replaces.")
+}
+
+func Test_ConsolidationSuggestions(t *testing.T) {
+	assert.Equal(t, []Replacement{replaceC}, testReplacements.ConsolidationSuggestions())
+
+	scopedB := replaceB
+	scopedB.OriginalVersion = "v0.4.3"
+	replacements := &Replacements{
+		main:            "test-module",
+		replacedModules: []string{"originalB"},
+		originToReplace: map[string][]Replacement{"originalB": {replaceB, scopedB}},
+	}
+	assert.Empty(t, replacements.ConsolidationSuggestions(), "Should not merge replaces of different versions of a module.")
+}
+
+func Test_TopLevelSnippet(t *testing.T) {
+	replacements := &Replacements{
+		main:            "test-module",
+		topLevel:        map[string]Replacement{"originalA": {Original: "originalA", Override: "overrideA"}},
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA},
+			"originalB": {replaceG},
+			"originalC": {replaceC},
+		},
+	}
+
+	writer := &strings.Builder{}
+	assert.NoError(t, replacements.TopLevelSnippet(writer))
+	assert.Equal(t, "replace (\n\toriginalB v0.4.3 => overrideB v1.0.0\n\toriginalC => ./overrideC\n)\n", writer.String())
+
+	writer.Reset()
+	assert.NoError(t, (&Replacements{}).TopLevelSnippet(writer))
+	assert.Empty(t, writer.String(), "Should not write anything if there is nothing to hoist.")
+}
+
+func Test_ValidateLocalPaths(t *testing.T) {
+	errs := testReplacements.FilterOnOffendingModule([]string{"moduleA"}).ValidateLocalPaths(".")
+	assert.Empty(t, errs, "Should find the go.mod of the local override.")
+
+	replacements := &Replacements{
+		replacedModules: []string{"originalC", "originalD"},
+		originToReplace: map[string][]Replacement{
+			"originalC": {replaceC},
+			"originalD": {replaceD},
+		},
+	}
+	errs = replacements.ValidateLocalPaths(".")
+	if assert.Len(t, errs, 1, "Should report the missing local override.") {
+		assert.Contains(t, errs[0].Error(), "./overrideD", "Should mention the broken path.")
+		assert.Contains(t, errs[0].Error(), "moduleA", "Should mention the offender.")
+	}
+}
+
+func Test_ExternalLocalReplaces(t *testing.T) {
+	escaping := Replacement{
+		Offender: moduleA,
+		Original: "originalE",
+		Override: "../../../elsewhere",
+	}
+	absolute := Replacement{
+		Offender: moduleA,
+		Original: "originalF",
+		Override: "/opt/originalF",
+	}
+	replacements := &Replacements{
+		replacedModules: []string{"originalB", "originalC", "originalE", "originalF"},
+		originToReplace: map[string][]Replacement{
+			"originalB": {replaceB},
+			"originalC": {replaceC},
+			"originalE": {escaping},
+			"originalF": {absolute},
+		},
+	}
+
+	assert.Equal(t, []Replacement{escaping, absolute}, replacements.ExternalLocalReplaces("."))
+	assert.Equal(t, []Replacement{escaping}, replacements.ExternalLocalReplaces("/opt"), "Should not report absolute overrides inside the root.")
+	assert.Equal(
+		t,
+		[]Replacement{escaping, absolute},
+		replacements.ExternalLocalReplaces("testdata/moduleA"),
+		"Should resolve overrides relative to the offender's directory.",
+	)
+}
+
+func Test_ValidateOverrideVersions(t *testing.T) {
+	replaceBis := replaceB
+	replaceBis.Offender = &depgraph.Module{Path: "offender"}
+	replacements := &Replacements{
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA},
+			"originalB": {replaceB, replaceBis},
+			"originalC": {replaceC},
+		},
+	}
+
+	var queried []string
+	errs := replacements.validateOverrideVersions(func(module string, version string) error {
+		queried = append(queried, module+"@"+version)
+		if module == "overrideB" {
+			return errors.New("unknown revision")
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"overrideA@v1.0.0", "overrideB@v1.0.0"}, queried, "Should query each override once and skip local paths.")
+	if assert.Len(t, errs, 2, "Should report each replacement with an unresolvable override.") {
+		assert.Contains(t, errs[0].Error(), "moduleA")
+		assert.Contains(t, errs[0].Error(), "unknown revision")
+		assert.Contains(t, errs[1].Error(), "offender")
+	}
+}
+
+func Test_SimplifiableVersions(t *testing.T) {
+	replaceMain := replaceE
+	replaceMain.Offender = &depgraph.Module{Path: "test-module"}
+	replacements := &Replacements{
+		main:            "test-module",
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, replaceMain},
+			"originalB": {replaceB, replaceF},
+			"originalC": {replaceC},
+		},
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	var queried []string
+	simplifiable := replacements.simplifiableVersions(logger, func(module string) (string, error) {
+		queried = append(queried, module)
+		switch module {
+		case "overrideA":
+			return "v1.0.0", nil
+		case "overrideB":
+			return "v1.2.0", nil
+		case "overrideB-bis":
+			return "v1.9.0", nil
+		default:
+			return "", errors.New("unknown module")
+		}
+	})
+	assert.Equal(t, []SimplifiableVersion{
+		{Replacement: replaceA, Latest: "v1.0.0"},
+		{Replacement: replaceB, Latest: "v1.2.0", Outdated: true},
+	}, simplifiable, "Should report overrides at or behind their latest version.")
+	assert.Equal(t, []string{"overrideA", "overrideB", "overrideB-bis"}, queried, "Should skip local paths and the main module.")
+}
+
+func Test_TopLevelImpact(t *testing.T) {
+	graph, err := depgraph.BuildFromGoMods(nil, "test-module", map[string]string{
+		"test-module": `module test-module
+
+require (
+	moduleA v1.0.0
+	originalC v1.0.0
+)
+
+replace originalB => overrideB v1.0.0
+`,
+		"moduleA": "module moduleA\n\nrequire originalB v0.4.3\n",
+	})
+	require.NoError(t, err)
+
+	replacements := &Replacements{
+		main: "test-module",
+		topLevel: map[string]Replacement{
+			"originalA": {Original: "originalA", Override: "overrideA"},
+			"originalB": {Original: "originalB", Override: "overrideB"},
+			"originalC": {Original: "originalC", Override: "./overrideC"},
+		},
+	}
+	assert.Equal(t, map[string]int{
+		"originalA": 0,
+		"originalB": 2,
+		"originalC": 1,
+	}, replacements.TopLevelImpact(graph))
+}
+
+func Test_DuplicateReplaces(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	parsed := parseGoModForReplacements(logger, moduleA, `module moduleA
+
+replace originalB => overrideB v1.0.0
+
+replace (
+	originalB => overrideB-bis v1.1.0
+	originalC v0.1.0 => ./overrideC
+	originalC v0.2.0 => ./overrideC
+	originalD => ./overrideD
+)
+`)
+	assert.Equal(t, []string{"originalB"}, duplicateOriginals(parsed), "Should only report modules replaced more than once.")
+
+	replacements, err := FindReplacementsInFiles(logger, "test/duplicates", []string{
+		filepath.Join("testdata", "duplicates", "main", "go.mod"),
+		filepath.Join("testdata", "duplicates", "dependency", "go.mod"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"test/duplicates": {"originalA"},
+		"dependency":      {"originalC@v0.1.0"},
+	}, replacements.DuplicateReplaces(), "Should report duplicates in the go.mod of the main module as well.")
+	assert.Equal(t, map[string][]string{
+		"dependency": {"originalC@v0.1.0"},
+	}, replacements.FilterOnOffendingModule([]string{"dependency"}).DuplicateReplaces(), "Should filter duplicates by offender.")
+	assert.Empty(t, replacements.FilterOnReplacedModule([]string{"originalB"}).DuplicateReplaces(), "Should filter duplicates by original.")
+
+	replacements, err = FindReplacementsWithOptions(logger, testGraph, FindOptions{IncludeVendor: true})
+	require.NoError(t, err)
+	assert.Empty(t, replacements.DuplicateReplaces(), "Should not mix up vendored and regular go.mod files.")
+}
+
+func Test_LocalPathConflicts(t *testing.T) {
+	sameDir := Replacement{Offender: moduleB, Original: "originalC", Override: "../moduleA/overrideC"}
+	otherDir := Replacement{Offender: &depgraph.Module{Path: "offender"}, Original: "originalC", Override: "./overrideC"}
+	module := Replacement{Offender: &depgraph.Module{Path: "offender-bis"}, Original: "originalD", Override: "overrideD", Version: "v1.0.0"}
+
+	consistent := &Replacements{
+		replacedModules: []string{"originalC"},
+		originToReplace: map[string][]Replacement{"originalC": {replaceC, sameDir}},
+	}
+	assert.Empty(t, consistent.Conflicts(), "Should compare local-path overrides by the directory they resolve to.")
+	assert.Empty(t, consistent.LocalPathConflicts("."))
+
+	inconsistent := &Replacements{
+		replacedModules: []string{"originalC", "originalD"},
+		originToReplace: map[string][]Replacement{
+			"originalC": {replaceC, sameDir, otherDir},
+			"originalD": {replaceD, module},
+		},
+	}
+	assert.Equal(t, map[string][]Replacement{
+		"originalC": {replaceC, sameDir, otherDir},
+		"originalD": {replaceD, module},
+	}, inconsistent.Conflicts())
+
+	root, err := filepath.Abs(".")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string][]Replacement{
+		"originalC": {
+			filepath.Join(root, "testdata", "moduleA", "overrideC"): {replaceC, sameDir},
+			filepath.Join(root, "offender", "overrideC"):            {otherDir},
+		},
+	}, inconsistent.LocalPathConflicts("."), "Should only report local-path overrides that resolve to different directories.")
+}
@@ -0,0 +1,89 @@
+package reveal
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+	"github.com/Helcaraxan/gomod/lib/internal/modfile"
+)
+
+// Exclusion represents an 'exclude' directive in the go.mod file of the Offender module.
+type Exclusion struct {
+	Offender *depgraph.Module
+	Module   string
+	Version  string
+}
+
+// Exclusions returns all the exclude directives that were found alongside the replacements, ordered
+// by excluded module and subsequently by offender.
+func (r *Replacements) Exclusions() []Exclusion {
+	return append([]Exclusion(nil), r.exclusions...)
+}
+
+func parseGoModForExclusions(logger *logrus.Logger, module *depgraph.Module, goModContent string) []Exclusion {
+	return exclusionsOf(logger, module, parseGoModContent(logger, module, goModContent))
+}
+
+// exclusionsOf returns the exclude directives of the parsed go.mod file of the specified module.
+func exclusionsOf(logger *logrus.Logger, module *depgraph.Module, goMod *modfile.File) []Exclusion {
+	var exclusions []Exclusion
+	for _, exclude := range goMod.Excludes {
+		exclusion := Exclusion{
+			Offender: module,
+			Module:   exclude.Path,
+			Version:  exclude.Version,
+		}
+		logger.Debugf("Found exclusion of %q at %q in dependency %q.", exclusion.Module, exclusion.Version, exclusion.Offender.Path)
+		exclusions = append(exclusions, exclusion)
+	}
+	return exclusions
+}
+
+func sortExclusions(exclusions []Exclusion) {
+	sort.Slice(exclusions, func(i int, j int) bool {
+		if exclusions[i].Module != exclusions[j].Module {
+			return exclusions[i].Module < exclusions[j].Module
+		}
+		if exclusions[i].Offender.Path != exclusions[j].Offender.Path {
+			return exclusions[i].Offender.Path < exclusions[j].Offender.Path
+		}
+		return exclusions[i].Version < exclusions[j].Version
+	})
+}
+
+// filterExclusions returns the exclusions for which the keep function returns true.
+func filterExclusions(exclusions []Exclusion, keep func(Exclusion) bool) []Exclusion {
+	var filtered []Exclusion
+	for _, exclusion := range exclusions {
+		if keep(exclusion) {
+			filtered = append(filtered, exclusion)
+		}
+	}
+	return filtered
+}
+
+// printExclusions formats the exclusions as aligned lines grouped per excluded module.
+func printExclusions(exclusions []Exclusion) string {
+	var maxOffenderLength int
+	for _, exclusion := range exclusions {
+		if len(exclusion.Offender.Path) > maxOffenderLength {
+			maxOffenderLength = len(exclusion.Offender.Path)
+		}
+	}
+	template := fmt.Sprintf("   %%-%ds @ %%s\n", maxOffenderLength)
+
+	var output string
+	for idx, exclusion := range exclusions {
+		if idx == 0 || exclusions[idx-1].Module != exclusion.Module {
+			output += fmt.Sprintf("'%s' is excluded:\n", exclusion.Module)
+		}
+		output += fmt.Sprintf(template, exclusion.Offender.Path, exclusion.Version)
+		if idx == len(exclusions)-1 || exclusions[idx+1].Module != exclusion.Module {
+			output += "\n"
+		}
+	}
+	return output
+}
@@ -0,0 +1,94 @@
+package reveal
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_ParseExclusions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	offender := &depgraph.Module{Path: "offender"}
+	testcases := map[string]struct {
+		input    string
+		expected []Exclusion
+	}{
+		"SingleExclude": {
+			input:    "exclude moduleA v1.0.0",
+			expected: []Exclusion{{Offender: offender, Module: "moduleA", Version: "v1.0.0"}},
+		},
+		"MultiExclude": {
+			input: `exclude (
+	moduleA v1.0.0
+	moduleB v0.2.0-rc1
+)`,
+			expected: []Exclusion{
+				{Offender: offender, Module: "moduleA", Version: "v1.0.0"},
+				{Offender: offender, Module: "moduleB", Version: "v0.2.0-rc1"},
+			},
+		},
+		"CommentedExclude": {
+			input: `// exclude moduleA v1.0.0
+exclude moduleB v1.0.0 // exclude moduleC v1.0.0`,
+			expected: []Exclusion{{Offender: offender, Module: "moduleB", Version: "v1.0.0"}},
+		},
+		"NoExclude": {
+			input: "replace originalA => overrideA v1.0.0",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseGoModForExclusions(logger, offender, tc.input))
+		})
+	}
+}
+
+func Test_PrintExclusions(t *testing.T) {
+	const expectedOutput = `'originalC' is replaced:
+   moduleA -> ./overrideC
+
+'originalB' is excluded:
+   moduleA     @ v0.9.0
+   test-module @ v0.9.1
+
+`
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	replacements := &Replacements{
+		main:            "test-module",
+		topLevel:        map[string]Replacement{},
+		replacedModules: []string{"originalC"},
+		originToReplace: map[string][]Replacement{"originalC": {replaceC}},
+		exclusions: []Exclusion{
+			{Offender: moduleA, Module: "originalB", Version: "v0.9.0"},
+			{Offender: &depgraph.Module{Path: "test-module"}, Module: "originalB", Version: "v0.9.1"},
+			{Offender: &depgraph.Module{Path: "test-module"}, Module: "originalD", Version: "v0.1.0"},
+		},
+	}
+
+	writer := &strings.Builder{}
+	assert.NoError(t, replacements.Print(logger, writer, nil, []string{"originalB", "originalC"}, PrintOpts{}))
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+	const expectedByOffender = `'moduleA' replaces:
+   originalC -> ./overrideC
+
+'originalB' is excluded:
+   moduleA     @ v0.9.0
+   test-module @ v0.9.1
+
+`
+
+	writer = &strings.Builder{}
+	assert.NoError(t, replacements.PrintByOffender(logger, writer, nil, []string{"originalB", "originalC"}, PrintOpts{}))
+	assert.Equal(t, expectedByOffender, writer.String(), "Should print the same exclusions when grouping by offender.")
+}
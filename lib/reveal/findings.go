@@ -0,0 +1,83 @@
+package reveal
+
+import (
+	"fmt"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+// Severity indicates how much a Finding matters. Higher values are more severe.
+type Severity int
+
+// Severities of the findings reported by Findings, from least to most severe.
+const (
+	SeverityInfo Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Finding is a problem detected with a replacement.
+type Finding struct {
+	Severity    Severity
+	Replacement Replacement
+	Reason      string
+}
+
+// Findings aggregates the problems detected with the replacements into a single list, ordered
+// from most to least severe: conflicting replacements, replacements that are not matched by a
+// top-level replace, stale replacements and finally no-op replacements. Stale replacements are
+// only detected if a dependency graph is given. Findings of the same severity are ordered in the
+// same way as the output of Print. A replacement can be part of several findings.
+func (r *Replacements) Findings(graph *depgraph.DepGraph) []Finding {
+	var findings []Finding
+	conflicts := r.Conflicts()
+	for _, original := range r.replacedModules {
+		for _, replace := range conflicts[original] {
+			findings = append(findings, Finding{
+				Severity:    SeverityHigh,
+				Replacement: replace,
+				Reason:      fmt.Sprintf("conflicts with other replaces of %q", original),
+			})
+		}
+	}
+	for _, replace := range r.UnmatchedTopLevel() {
+		findings = append(findings, Finding{
+			Severity:    SeverityMedium,
+			Replacement: replace,
+			Reason:      fmt.Sprintf("not matched by a top-level replace in %q", r.main),
+		})
+	}
+	if graph != nil {
+		for _, replace := range r.StaleAgainst(graph) {
+			findings = append(findings, Finding{
+				Severity:    SeverityLow,
+				Replacement: replace,
+				Reason:      "override is not newer than the selected version",
+			})
+		}
+	}
+	for _, replace := range r.NoOpReplaces() {
+		findings = append(findings, Finding{
+			Severity:    SeverityInfo,
+			Replacement: replace,
+			Reason:      "replaces a module by itself",
+		})
+	}
+	return findings
+}
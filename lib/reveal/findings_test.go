@@ -0,0 +1,48 @@
+package reveal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_Findings(t *testing.T) {
+	mainModule := &depgraph.Module{Main: true, Path: "test-module"}
+	noOp := Replacement{Offender: moduleA, Original: "originalD", OriginalVersion: "v1.0.0", Override: "originalD", Version: "v1.0.0"}
+	topLevelNoOp := Replacement{Offender: mainModule, Original: "originalD", Override: "originalD"}
+	pinB := Replacement{Offender: moduleA, Original: "originalB", Override: "originalB", Version: "v1.0.0"}
+	replacements := &Replacements{
+		main: "test-module",
+		topLevel: map[string]Replacement{
+			"originalA": {Offender: mainModule, Original: "originalA", Override: "overrideA"},
+			"originalB": {Offender: mainModule, Original: "originalB", Override: "originalB", Version: "v1.0.0"},
+			"originalD": topLevelNoOp,
+		},
+		replacedModules: []string{"originalA", "originalB", "originalC", "originalD"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, replaceE},
+			"originalB": {pinB},
+			"originalC": {replaceC},
+			"originalD": {noOp},
+		},
+	}
+	graph := depgraph.NewGraph(nil, mainModule)
+	graph.AddNode(&depgraph.Module{Path: "originalA", Version: "v1.5.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalB", Version: "v1.2.0"})
+
+	findings := replacements.Findings(graph)
+	var severities []Severity
+	var found []Replacement
+	for _, finding := range findings {
+		severities = append(severities, finding.Severity)
+		found = append(found, finding.Replacement)
+	}
+	assert.Equal(t, []Severity{SeverityHigh, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo, SeverityInfo}, severities)
+	assert.Equal(t, []Replacement{replaceA, replaceE, replaceC, pinB, topLevelNoOp, noOp}, found)
+	assert.Contains(t, findings[0].Reason, "originalA")
+
+	assert.Len(t, replacements.Findings(nil), 5, "Should skip stale replacements without a graph.")
+	assert.Equal(t, "medium", SeverityMedium.String())
+}
@@ -0,0 +1,89 @@
+package reveal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+	"github.com/Helcaraxan/gomod/lib/internal/util"
+)
+
+// goModEdit contains the information that is used from the output of 'go mod edit -json'.
+type goModEdit struct {
+	Module struct {
+		Path string
+	}
+	Replace []struct {
+		Old struct {
+			Path    string
+			Version string
+		}
+		New struct {
+			Path    string
+			Version string
+		}
+	}
+}
+
+// CompareWithGoModEdit parses the replace directives of the specified go.mod file and compares them
+// with the ones reported by 'go mod edit -json' for the same file. It returns the replacements that
+// were only found by the parser, in the order of the file, followed by the ones that were only
+// reported by 'go'. An empty result means that both agree. The 'logger' parameter can be 'nil' which
+// will result in no output or logging information to be provided.
+func CompareWithGoModEdit(logger *logrus.Logger, goModPath string) ([]Replacement, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	rawGoMod, err := ioutil.ReadFile(goModPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod file %q: %v", goModPath, err)
+	}
+	rawEdit, err := util.RunCommand(logger, true, "go", "mod", "edit", "-json", goModPath)
+	if err != nil {
+		return nil, err
+	}
+	return compareWithGoModEdit(logger, goModPath, string(rawGoMod), rawEdit)
+}
+
+func compareWithGoModEdit(logger *logrus.Logger, goModPath string, goModContent string, rawEdit []byte) ([]Replacement, error) {
+	var edit goModEdit
+	if err := json.Unmarshal(rawEdit, &edit); err != nil {
+		return nil, fmt.Errorf("unable to parse 'go mod edit -json' output for %q: %v", goModPath, err)
+	}
+	module := &depgraph.Module{Path: edit.Module.Path, GoMod: goModPath}
+
+	var canonical []Replacement
+	for _, replace := range edit.Replace {
+		canonical = append(canonical, Replacement{
+			Offender:        module,
+			Original:        replace.Old.Path,
+			OriginalVersion: replace.Old.Version,
+			Override:        replace.New.Path,
+			Version:         replace.New.Version,
+		})
+	}
+	parsed := parseGoModForReplacements(logger, module, goModContent)
+
+	key := func(replace Replacement) string {
+		return fmt.Sprintf("%s %s => %s %s", replace.Original, replace.OriginalVersion, replace.Override, replace.Version)
+	}
+	difference := func(lhs []Replacement, rhs []Replacement) []Replacement {
+		known := map[string]struct{}{}
+		for _, replace := range rhs {
+			known[key(replace)] = struct{}{}
+		}
+		var missing []Replacement
+		for _, replace := range lhs {
+			if _, ok := known[key(replace)]; !ok {
+				missing = append(missing, replace)
+			}
+		}
+		return missing
+	}
+	return append(difference(parsed, canonical), difference(canonical, parsed)...), nil
+}
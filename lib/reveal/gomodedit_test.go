@@ -0,0 +1,47 @@
+package reveal
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_CompareWithGoModEdit(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	discrepancies, err := CompareWithGoModEdit(logger, filepath.Join("testdata", "moduleA", "go.mod"))
+	require.NoError(t, err)
+	assert.Empty(t, discrepancies, "Should agree with 'go mod edit' on the test go.mod.")
+
+	const goModEditOutput = `{
+	"Module": {"Path": "moduleA"},
+	"Replace": [
+		{"Old": {"Path": "originalB"}, "New": {"Path": "overrideB", "Version": "v1.0.0"}},
+		{"Old": {"Path": "originalE", "Version": "v0.1.0"}, "New": {"Path": "overrideE", "Version": "v0.2.0"}}
+	]
+}`
+	const goModContent = `module moduleA
+
+replace (
+	originalB => overrideB v1.0.0
+	originalC => ./overrideC
+)
+`
+	module := &depgraph.Module{Path: "moduleA", GoMod: "go.mod"}
+	discrepancies, err = compareWithGoModEdit(logger, "go.mod", goModContent, []byte(goModEditOutput))
+	require.NoError(t, err)
+	assert.Equal(t, []Replacement{
+		{Offender: module, Original: "originalC", Override: "./overrideC", Line: 5},
+		{Offender: module, Original: "originalE", OriginalVersion: "v0.1.0", Override: "overrideE", Version: "v0.2.0"},
+	}, discrepancies)
+
+	_, err = compareWithGoModEdit(logger, "go.mod", goModContent, []byte("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package reveal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsonReplacements struct {
+	Main         string            `json:"main"`
+	TopLevel     map[string]string `json:"topLevel"`
+	Replacements []jsonOriginal    `json:"replacements"`
+	Exclusions   []jsonExclusion   `json:"exclusions,omitempty"`
+}
+
+type jsonExclusion struct {
+	Offender string `json:"offender"`
+	Module   string `json:"module"`
+	Version  string `json:"version"`
+}
+
+type jsonOriginal struct {
+	Original string            `json:"original"`
+	Replaces []jsonReplacement `json:"replaces"`
+}
+
+type jsonReplacement struct {
+	Offender           string   `json:"offender"`
+	Offenders          []string `json:"offenders,omitempty"`
+	Original           string   `json:"original"`
+	OriginalVersion    string   `json:"originalVersion,omitempty"`
+	Override           string   `json:"override"`
+	Version            string   `json:"version,omitempty"`
+	MatchesTopLevel    bool     `json:"matchesTopLevel"`
+	OffenderIsReplaced bool     `json:"offenderIsReplaced,omitempty"`
+	Vendored           bool     `json:"vendored,omitempty"`
+	Line               int      `json:"line,omitempty"`
+	ChainDepth         int      `json:"chainDepth,omitempty"`
+	Resolved           string   `json:"resolved,omitempty"`
+}
+
+// WriteJSON writes the replacements in JSON format to the specified writer. The replacements are
+// grouped per replaced module in the same order as the output of Print. Each replacement indicates
+// whether it matches a replace directive in the main module's go.mod.
+func (r *Replacements) WriteJSON(w io.Writer) error {
+	output := jsonReplacements{
+		Main:         r.main,
+		TopLevel:     make(map[string]string, len(r.topLevel)),
+		Replacements: []jsonOriginal{},
+	}
+	for original, replace := range r.topLevel {
+		output.TopLevel[original] = replace.Override
+	}
+	for _, original := range r.replacedModules {
+		entry := jsonOriginal{Original: original}
+		for _, replacement := range r.originToReplace[original] {
+			replace := jsonReplacement{
+				Offender:           replacement.Offender.Path,
+				Original:           replacement.Original,
+				OriginalVersion:    replacement.OriginalVersion,
+				Override:           replacement.Override,
+				Version:            replacement.Version,
+				MatchesTopLevel:    r.matchesTopLevel(replacement),
+				OffenderIsReplaced: r.offenderIsReplaced(replacement),
+				Vendored:           replacement.Vendored,
+				Line:               replacement.Line,
+				ChainDepth:         replacement.ChainDepth,
+				Resolved:           replacement.Resolved,
+			}
+			for _, offender := range replacement.Offenders {
+				replace.Offenders = append(replace.Offenders, offender.Path)
+			}
+			entry.Replaces = append(entry.Replaces, replace)
+		}
+		output.Replacements = append(output.Replacements, entry)
+	}
+
+	for _, exclusion := range r.exclusions {
+		output.Exclusions = append(output.Exclusions, jsonExclusion{
+			Offender: exclusion.Offender.Path,
+			Module:   exclusion.Module,
+			Version:  exclusion.Version,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("failed to write replacements as JSON: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package reveal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteJSON(t *testing.T) {
+	const expectedOutput = `{
+  "main": "test-module",
+  "topLevel": {
+    "originalA": "overrideA",
+    "originalB": "overrideB-bis"
+  },
+  "replacements": [
+    {
+      "original": "originalA",
+      "replaces": [
+        {
+          "offender": "offender",
+          "original": "originalA",
+          "override": "overrideA",
+          "version": "v1.0.0",
+          "matchesTopLevel": true
+        },
+        {
+          "offender": "offender-bis",
+          "original": "originalA",
+          "override": "overrideA-bis",
+          "version": "v2.0.0",
+          "matchesTopLevel": false
+        }
+      ]
+    },
+    {
+      "original": "originalC",
+      "replaces": [
+        {
+          "offender": "moduleA",
+          "original": "originalC",
+          "override": "./overrideC",
+          "matchesTopLevel": false
+        }
+      ]
+    }
+  ]
+}
+`
+
+	writer := &strings.Builder{}
+	filtered := testReplacements.FilterOnReplacedModule([]string{"originalA", "originalC"})
+	assert.NoError(t, filtered.WriteJSON(writer), "Should not fail to write JSON.")
+	assert.Equal(t, expectedOutput, writer.String(), "Should have written the expected JSON.")
+}
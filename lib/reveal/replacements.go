@@ -6,42 +6,210 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/Helcaraxan/gomod/lib/depgraph"
+	"github.com/Helcaraxan/gomod/lib/internal/modfile"
+	"github.com/Helcaraxan/gomod/lib/internal/util"
 )
 
 type Replacement struct {
-	Offender *depgraph.Module
-	Original string
-	Override string
-	Version  string
+	Offender        *depgraph.Module
+	Original        string
+	OriginalVersion string // only set if the replace is restricted to a specific version of the original
+	Override        string
+	Version         string
+	Vendored        bool // found in a go.mod file inside the main module's vendor directory
+	Line            int  // 1-based line of the go.mod file at which the replace directive appears
+	// Number of further replacements that were followed from Override to reach Resolved. Only set
+	// when replace chains are resolved via FindOptions.
+	ChainDepth int
+	Resolved   string // final override at the end of the replace chain, if ChainDepth is non-zero
+	// All the modules that declare this exact replacement, including Offender. Only set on
+	// replacements returned by Deduplicate.
+	Offenders []*depgraph.Module
+}
+
+// PrintOpts controls the presentation of the output of Print and PrintByOffender.
+type PrintOpts struct {
+	// Use ANSI escape codes to colour replacements that match a top-level replace in green, those of
+	// modules with conflicting replacements in red and local-path overrides in yellow. Whether the
+	// output supports colours is left for the caller to determine.
+	Color bool
+	// Split the output in a first section holding the replacements that do not match a top-level
+	// replace followed by a second one holding those that do. The order of the replacements within
+	// each section is the same as without grouping.
+	GroupByMatch bool
 }
 
 type Replacements struct {
 	main     string
-	topLevel map[string]string
+	topLevel map[string]Replacement
 
 	replacedModules []string
 	originToReplace map[string][]Replacement
+
+	exclusions []Exclusion
+	// Modules that are replaced more than once by the same go.mod file, keyed by the offender.
+	duplicates map[string][]string
 }
 
-func (r *Replacements) Print(logger *logrus.Logger, writer io.Writer, offenders []string, targets []string) error {
+func (r *Replacements) Print(logger *logrus.Logger, writer io.Writer, offenders []string, targets []string, opts PrintOpts) error {
 	filtered := r.FilterOnOffendingModule(offenders).FilterOnReplacedModule(targets)
 
 	var (
 		output     string
 		matchFound bool
 	)
-	for _, origin := range filtered.replacedModules {
-		newOutput, match := filtered.printModuleReplacements(origin)
-		output += newOutput
-		matchFound = matchFound || match
+	for _, section := range filtered.sections(opts) {
+		output += section.title
+		for _, origin := range section.replacements.replacedModules {
+			newOutput, match := section.replacements.printModuleReplacements(origin, opts)
+			output += newOutput
+			matchFound = matchFound || match
+		}
+	}
+	output += printExclusions(filtered.exclusions)
+	return r.writeOutput(writer, output, matchFound)
+}
+
+// PrintByOffender is a variant of Print that groups the replacements by the module whose go.mod
+// contains them instead of by the module that is being replaced. Deduplicated replacements are
+// listed for each of their offenders. Exclusions are filtered and printed in the same way as by Print.
+func (r *Replacements) PrintByOffender(logger *logrus.Logger, writer io.Writer, offenders []string, targets []string, opts PrintOpts) error {
+	filtered := r.FilterOnOffendingModule(offenders).FilterOnReplacedModule(targets)
+
+	var (
+		output     string
+		matchFound bool
+	)
+	for _, section := range filtered.sections(opts) {
+		output += section.title
+		byOffender := map[string][]Replacement{}
+		for _, origin := range section.replacements.replacedModules {
+			for _, replacement := range section.replacements.originToReplace[origin] {
+				for _, offender := range replacementOffenders(replacement) {
+					byOffender[offender.Path] = append(byOffender[offender.Path], replacement)
+				}
+			}
+		}
+		offenderPaths := make([]string, 0, len(byOffender))
+		for offender := range byOffender {
+			offenderPaths = append(offenderPaths, offender)
+		}
+		sort.Strings(offenderPaths)
+
+		for _, offender := range offenderPaths {
+			newOutput, match := section.replacements.printOffenderReplacements(offender, byOffender[offender], opts)
+			output += newOutput
+			matchFound = matchFound || match
+		}
 	}
+	output += printExclusions(filtered.exclusions)
+	return r.writeOutput(writer, output, matchFound)
+}
 
+// replacementSection is a block of the output of Print and PrintByOffender.
+type replacementSection struct {
+	title        string
+	replacements *Replacements
+}
+
+// sections splits the replacements in the blocks that should be printed according to the given
+// PrintOpts. Sections without any replacement are omitted.
+func (r *Replacements) sections(opts PrintOpts) []replacementSection {
+	if !opts.GroupByMatch {
+		return []replacementSection{{replacements: r}}
+	}
+	candidates := []replacementSection{
+		{
+			title:        "Replacements without a matching top-level replace:\n\n",
+			replacements: r.filter(func(replace Replacement) bool { return !r.matchesTopLevel(replace) }),
+		},
+		{
+			title:        "Replacements with a matching top-level replace:\n\n",
+			replacements: r.filter(r.matchesTopLevel),
+		},
+	}
+	var sections []replacementSection
+	for _, section := range candidates {
+		if len(section.replacements.replacedModules) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// PrintTSV writes one tab-separated line per replacement to the specified writer. Each line holds
+// the original, offender, override and version of the replacement followed by whether it matches a
+// top-level replace. Lines are ordered in the same way as the output of Print.
+func (r *Replacements) PrintTSV(writer io.Writer) error {
+	var output strings.Builder
+	for _, origin := range r.replacedModules {
+		for _, replacement := range r.originToReplace[origin] {
+			fmt.Fprintf(
+				&output,
+				"%s\t%s\t%s\t%s\t%t\n",
+				replacement.Original,
+				replacement.Offender.Path,
+				replacement.Override,
+				replacement.Version,
+				r.matchesTopLevel(replacement),
+			)
+		}
+	}
+
+	if _, err := io.WriteString(writer, output.String()); err != nil {
+		return fmt.Errorf("failed to print replacements: %v", err)
+	}
+	return nil
+}
+
+// PrintTree writes the replacements as a tree to the specified writer. Each replaced module is a
+// root with the modules that replace it as its branches. Replaced modules for which one of the
+// replacements matches a top-level replace are marked as such.
+func (r *Replacements) PrintTree(writer io.Writer) error {
+	var (
+		output     strings.Builder
+		matchFound bool
+	)
+	for _, origin := range r.replacedModules {
+		replacements := r.originToReplace[origin]
+
+		var match bool
+		for _, replacement := range replacements {
+			match = match || r.matchesTopLevel(replacement)
+		}
+		output.WriteString(origin)
+		if match {
+			output.WriteString(" ✓")
+			matchFound = true
+		}
+		output.WriteString("\n")
+
+		for idx, replacement := range replacements {
+			connector := "├── "
+			if idx == len(replacements)-1 {
+				connector = "└── "
+			}
+			output.WriteString(connector + offenderNames(replacement) + " -> " + replacement.Override)
+			if replacement.Version != "" {
+				output.WriteString(" @ " + replacement.Version)
+			}
+			if replacement.Vendored {
+				output.WriteString(" (vendored)")
+			}
+			output.WriteString("\n")
+		}
+		output.WriteString("\n")
+	}
+	return r.writeOutput(writer, output.String(), matchFound)
+}
+
+func (r *Replacements) writeOutput(writer io.Writer, output string, matchFound bool) error {
 	if matchFound {
 		output += fmt.Sprintf("[✓] Match with a top-level replace in '%s'\n", r.main)
 	}
@@ -52,46 +220,34 @@ func (r *Replacements) Print(logger *logrus.Logger, writer io.Writer, offenders
 	return nil
 }
 
+// FilterOnOffendingModule returns a copy of the replacements that only retains those declared by
+// one of the specified offenders. Deduplicated replacements are retained if any of their offenders
+// is among them.
 func (r *Replacements) FilterOnOffendingModule(offenders []string) *Replacements {
 	if len(offenders) == 0 {
 		return r
 	}
-	sort.Strings(offenders)
 
-	filtered := &Replacements{
-		main:            r.main,
-		topLevel:        map[string]string{},
-		originToReplace: map[string][]Replacement{},
-	}
-	for k, v := range r.topLevel {
-		filtered.topLevel[k] = v
+	keep := map[string]struct{}{}
+	for _, offender := range offenders {
+		keep[offender] = struct{}{}
 	}
-
-	for _, origin := range r.replacedModules {
-		unfilteredReplaces := r.originToReplace[origin]
-
-		var filteredReplaces []Replacement
-		var rIdx, oIdx int
-		for {
-			if rIdx == len(unfilteredReplaces) || oIdx == len(offenders) {
-				break
+	filtered := r.filter(func(replace Replacement) bool {
+		for _, offender := range replacementOffenders(replace) {
+			if _, ok := keep[offender.Path]; ok {
+				return true
 			}
-			switch {
-			case unfilteredReplaces[rIdx].Offender.Path == offenders[oIdx]:
-				filteredReplaces = append(filteredReplaces, unfilteredReplaces[rIdx])
-				rIdx++
-				oIdx++
-			case unfilteredReplaces[rIdx].Offender.Path < offenders[oIdx]:
-				rIdx++
-			case unfilteredReplaces[rIdx].Offender.Path > offenders[oIdx]:
-				oIdx++
-			}
-		}
-		if len(filteredReplaces) != 0 {
-			filtered.replacedModules = append(filtered.replacedModules, origin)
-			filtered.originToReplace[origin] = filteredReplaces
 		}
-	}
+		return false
+	})
+	filtered.exclusions = filterExclusions(r.exclusions, func(exclusion Exclusion) bool {
+		_, ok := keep[exclusion.Offender.Path]
+		return ok
+	})
+	filtered.duplicates = r.filterDuplicates(func(offender string, _ string) bool {
+		_, ok := keep[offender]
+		return ok
+	})
 	return filtered
 }
 
@@ -103,12 +259,25 @@ func (r *Replacements) FilterOnReplacedModule(originals []string) *Replacements
 
 	filtered := &Replacements{
 		main:            r.main,
-		topLevel:        map[string]string{},
+		topLevel:        map[string]Replacement{},
 		originToReplace: map[string][]Replacement{},
 	}
 	for k, v := range r.topLevel {
 		filtered.topLevel[k] = v
 	}
+	keep := map[string]struct{}{}
+	for _, original := range originals {
+		keep[original] = struct{}{}
+	}
+	filtered.exclusions = filterExclusions(r.exclusions, func(exclusion Exclusion) bool {
+		_, ok := keep[exclusion.Module]
+		return ok
+	})
+	filtered.duplicates = r.filterDuplicates(func(_ string, original string) bool {
+		_, ok := keep[strings.SplitN(original, "@", 2)[0]]
+		return ok
+	})
+
 	for _, original := range originals {
 		if len(r.originToReplace[original]) == 0 {
 			continue
@@ -121,20 +290,172 @@ func (r *Replacements) FilterOnReplacedModule(originals []string) *Replacements
 	return filtered
 }
 
-func (r *Replacements) printModuleReplacements(original string) (string, bool) {
+func (r *Replacements) FilterOnOverride(overrides []string) *Replacements {
+	if len(overrides) == 0 {
+		return r
+	}
+	keep := map[string]struct{}{}
+	for _, override := range overrides {
+		keep[override] = struct{}{}
+	}
+	return r.filter(func(replace Replacement) bool {
+		_, ok := keep[replace.Override]
+		return ok
+	})
+}
+
+// FilterNonMain returns a copy of the replacements without the modules that are replaced by the
+// main module, either via its go.mod or via a go.work file, as well as without any replacement
+// declared by the main module itself. This leaves the replaces of dependencies that are not already
+// overridden at the top-level.
+func (r *Replacements) FilterNonMain() *Replacements {
+	return r.filter(func(replace Replacement) bool {
+		if _, ok := r.topLevel[replace.Original]; ok {
+			return false
+		}
+		return replace.Offender.Path != r.main
+	})
+}
+
+// filter returns a copy of the replacements that only retains those for which 'keep' returns true.
+// Modules without any retained replacement are dropped while exclusions and duplicate replaces are
+// kept as-is.
+func (r *Replacements) filter(keep func(Replacement) bool) *Replacements {
+	filtered := &Replacements{
+		main:            r.main,
+		topLevel:        map[string]Replacement{},
+		originToReplace: map[string][]Replacement{},
+		exclusions:      r.exclusions,
+		duplicates:      r.duplicates,
+	}
+	for k, v := range r.topLevel {
+		filtered.topLevel[k] = v
+	}
+	for _, origin := range r.replacedModules {
+		var filteredReplaces []Replacement
+		for _, replace := range r.originToReplace[origin] {
+			if keep(replace) {
+				filteredReplaces = append(filteredReplaces, replace)
+			}
+		}
+		if len(filteredReplaces) != 0 {
+			filtered.replacedModules = append(filtered.replacedModules, origin)
+			filtered.originToReplace[origin] = filteredReplaces
+		}
+	}
+	return filtered
+}
+
+// Deduplicate returns a copy of the replacements in which identical replacements of the same module
+// by different offenders have been collapsed into a single one. The Offender of each collapsed
+// replacement is the first offender in alphabetical order while its Offenders field lists all of
+// them.
+func (r *Replacements) Deduplicate() *Replacements {
+	deduplicated := &Replacements{
+		main:            r.main,
+		topLevel:        map[string]Replacement{},
+		originToReplace: map[string][]Replacement{},
+		exclusions:      r.exclusions,
+		duplicates:      r.duplicates,
+	}
+	for k, v := range r.topLevel {
+		deduplicated.topLevel[k] = v
+	}
+	for _, origin := range r.replacedModules {
+		type directive struct {
+			originalVersion string
+			override        string
+			version         string
+			vendored        bool
+		}
+
+		var unique []Replacement
+		index := map[directive]int{}
+		for _, replace := range r.originToReplace[origin] {
+			key := directive{
+				originalVersion: replace.OriginalVersion,
+				override:        replace.Override,
+				version:         replace.Version,
+				vendored:        replace.Vendored,
+			}
+			offenders := replace.Offenders
+			if len(offenders) == 0 {
+				offenders = []*depgraph.Module{replace.Offender}
+			}
+			if idx, ok := index[key]; ok {
+				unique[idx].Offenders = append(unique[idx].Offenders, offenders...)
+				continue
+			}
+			index[key] = len(unique)
+			replace.Offenders = append([]*depgraph.Module(nil), offenders...)
+			unique = append(unique, replace)
+		}
+		deduplicated.replacedModules = append(deduplicated.replacedModules, origin)
+		deduplicated.originToReplace[origin] = unique
+	}
+	return deduplicated
+}
+
+// replacementOffenders returns all the modules that declare the replacement.
+func replacementOffenders(replacement Replacement) []*depgraph.Module {
+	if len(replacement.Offenders) == 0 {
+		return []*depgraph.Module{replacement.Offender}
+	}
+	return replacement.Offenders
+}
+
+// offenderNames returns the comma-separated paths of the modules that declare the replacement.
+func offenderNames(replacement Replacement) string {
+	if len(replacement.Offenders) == 0 {
+		return replacement.Offender.Path
+	}
+	names := make([]string, 0, len(replacement.Offenders))
+	for _, offender := range replacement.Offenders {
+		names = append(names, offender.Path)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (r *Replacements) printModuleReplacements(original string, opts PrintOpts) (string, bool) {
+	return r.printReplacementGroup(
+		fmt.Sprintf("'%s' is replaced:\n", original),
+		r.originToReplace[original],
+		offenderNames,
+		opts,
+	)
+}
+
+func (r *Replacements) printOffenderReplacements(offender string, replacements []Replacement, opts PrintOpts) (string, bool) {
+	return r.printReplacementGroup(
+		fmt.Sprintf("'%s' replaces:\n", offender),
+		replacements,
+		func(replacement Replacement) string { return replacement.Original },
+		opts,
+	)
+}
+
+// printReplacementGroup formats the given replacements as aligned lines below the specified header.
+// The left-hand side of each line is determined by the 'name' function while the right-hand side is
+// the override of the replacement.
+func (r *Replacements) printReplacementGroup(
+	header string,
+	replacements []Replacement,
+	name func(Replacement) string,
+	opts PrintOpts,
+) (string, bool) {
 	const (
 		matchedMark   = " ✓ "
 		unmatchedMark = "   "
 	)
 	var (
-		maxOffenderLength int
+		maxNameLength     int
 		maxOverrideLength int
 		maxVersionLength  int
 	)
 
-	for _, replacement := range r.originToReplace[original] {
-		if len(replacement.Offender.Path) > maxOffenderLength {
-			maxOffenderLength = len(replacement.Offender.Path)
+	for _, replacement := range replacements {
+		if len(name(replacement)) > maxNameLength {
+			maxNameLength = len(name(replacement))
 		}
 		if len(replacement.Override) > maxOverrideLength {
 			maxOverrideLength = len(replacement.Override)
@@ -143,91 +464,314 @@ func (r *Replacements) printModuleReplacements(original string) (string, bool) {
 			maxVersionLength = len(replacement.Version)
 		}
 	}
-	nonVersionedReplaceTemplate := fmt.Sprintf("%%-%ds -> %%-%ds", maxOffenderLength, maxOverrideLength)
+	nonVersionedReplaceTemplate := fmt.Sprintf("%%-%ds -> %%-%ds", maxNameLength, maxOverrideLength)
 	versionedReplaceTemplate := fmt.Sprintf("%s @ %%%ds", nonVersionedReplaceTemplate, maxVersionLength)
 
-	output := fmt.Sprintf("'%s' is replaced:\n", original)
+	output := header
 
 	var foundMatch bool
-	for _, replacement := range r.originToReplace[original] {
-		if topLevelOverride, ok := r.topLevel[replacement.Original]; ok && topLevelOverride == replacement.Override {
-			output += matchedMark
+	for _, replacement := range replacements {
+		line := unmatchedMark
+		if r.matchesTopLevel(replacement) {
+			line = matchedMark
 			foundMatch = true
-		} else {
-			output += unmatchedMark
 		}
 		if replacement.Version != "" {
-			output += fmt.Sprintf(versionedReplaceTemplate, replacement.Offender.Path, replacement.Override, replacement.Version)
+			line += fmt.Sprintf(versionedReplaceTemplate, name(replacement), replacement.Override, replacement.Version)
 		} else {
-			output += fmt.Sprintf(nonVersionedReplaceTemplate, replacement.Offender.Path, replacement.Override)
+			line += fmt.Sprintf(nonVersionedReplaceTemplate, name(replacement), replacement.Override)
+		}
+		if replacement.Vendored {
+			line += " (vendored)"
+		}
+		if r.offenderIsReplaced(replacement) {
+			line += " (offender is replaced)"
+		}
+		if location := replacementLocation(replacement); location != "" {
+			line += " (" + location + ")"
 		}
-		output += "\n"
+		if opts.Color {
+			line = r.colorize(replacement, line)
+		}
+		output += line + "\n"
 	}
 	return output + "\n", foundMatch
 }
 
-var (
-	singleReplaceRE = regexp.MustCompile("replace ([^\n]+)")
-	multiReplaceRE  = regexp.MustCompile("replace \\(([^)]+)\\)")
-	replaceRE       = regexp.MustCompile("([^\\s]+) => ([^\\s]+)(?: (v[^\\s]+))?")
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
 )
 
+// colorize wraps the printed line of the replacement in the ANSI colour codes described by
+// PrintOpts. Matching a top-level replace takes precedence over conflicts which in turn take
+// precedence over local-path overrides.
+func (r *Replacements) colorize(replacement Replacement, line string) string {
+	switch {
+	case r.matchesTopLevel(replacement):
+		return colorGreen + line + colorReset
+	case r.hasConflict(replacement.Original):
+		return colorRed + line + colorReset
+	case util.IsLocalPath(replacement.Override):
+		return colorYellow + line + colorReset
+	default:
+		return line
+	}
+}
+
+// replacementLocation returns the position of the replace directive in its go.mod file in the
+// 'path/go.mod:line' format. It is empty if the line is unknown or if the replacement has been
+// deduplicated across several offenders.
+func replacementLocation(replacement Replacement) string {
+	if replacement.Line == 0 || len(replacement.Offenders) > 1 {
+		return ""
+	}
+	return fmt.Sprintf("%s/go.mod:%d", replacement.Offender.Path, replacement.Line)
+}
+
+// offenderIsReplaced returns whether the module declaring the replacement is itself replaced by
+// another module, be it at the top-level or not. Such a replacement is most likely dead as the
+// offender's go.mod is not the one that ends up being used.
+func (r *Replacements) offenderIsReplaced(replacement Replacement) bool {
+	for _, offender := range replacementOffenders(replacement) {
+		if _, ok := r.topLevel[offender.Path]; ok {
+			return true
+		}
+		if _, ok := r.originToReplace[offender.Path]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesTopLevel returns whether the given replacement is identical to a replace directive of the
+// same module in the main module's go.mod.
+func (r *Replacements) matchesTopLevel(replacement Replacement) bool {
+	topLevel, ok := r.topLevel[replacement.Original]
+	return ok && topLevel.Override == replacement.Override
+}
+
+// FindOptions allows to tune which go.mod files are considered by FindReplacementsWithOptions.
+type FindOptions struct {
+	// Also consider the go.mod files of the modules in the main module's vendor directory. The
+	// replacements that are found there are marked as Vendored.
+	IncludeVendor bool
+	// Follow replacements whose override is itself replaced and record the final override of the
+	// chain on each replacement.
+	ResolveChains bool
+}
+
 func FindReplacements(logger *logrus.Logger, graph *depgraph.DepGraph) (*Replacements, error) {
+	return FindReplacementsWithOptions(logger, graph, FindOptions{})
+}
+
+// FindReplacementsWithOptions is a variant of FindReplacements that takes into account the given
+// options when searching for replacements.
+func FindReplacementsWithOptions(logger *logrus.Logger, graph *depgraph.DepGraph, opts FindOptions) (*Replacements, error) {
 	replacements := &Replacements{
 		main:            graph.Main().Name(),
-		topLevel:        map[string]string{},
+		topLevel:        map[string]Replacement{},
 		originToReplace: map[string][]Replacement{},
 	}
 
-	replaces, err := parseGoMod(logger, graph.Main().Module, replacements.topLevel, graph.Main().Module)
+	replaces, _, err := replacements.parseGoMod(logger, graph.Main().Module, graph.Main().Module)
 	if err != nil {
 		return nil, err
 	}
 	for _, replace := range replaces {
-		replacements.topLevel[replace.Original] = replace.Override
+		replacements.topLevel[replace.Original] = replace
+	}
+	workspaceReplaces, err := parseGoWork(logger, graph.Main().Module)
+	if err != nil {
+		return nil, err
+	}
+	replacements.recordDuplicates(graph.Main().Name(), workspaceReplaces)
+	for _, replace := range workspaceReplaces {
+		replacements.topLevel[replace.Original] = replace
 	}
 
+	var found []Replacement
 	for _, node := range graph.Nodes() {
-		replaces, err = parseGoMod(logger, graph.Main().Module, replacements.topLevel, node.Module)
+		var exclusions []Exclusion
+		replaces, exclusions, err = replacements.parseGoMod(logger, graph.Main().Module, node.Module)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, replaces...)
+		replacements.exclusions = append(replacements.exclusions, exclusions...)
+	}
+	if opts.IncludeVendor {
+		replaces, err = replacements.parseVendoredGoMods(logger, graph)
 		if err != nil {
 			return nil, err
 		}
+		found = append(found, replaces...)
+	}
 
-		for _, replace := range replaces {
-			replaces, ok := replacements.originToReplace[replace.Original]
-			if !ok {
-				replacements.replacedModules = append(replacements.replacedModules, replace.Original)
+	replacements.index(found)
+	if opts.ResolveChains {
+		replacements.resolveChains(logger)
+	}
+	return replacements, nil
+}
+
+// FindReplacementsInFiles is a variant of FindReplacements that parses the specified go.mod files
+// instead of those of the modules of a dependency graph. The module to which each file belongs is
+// determined by its module directive and the replace directives of the file belonging to 'main' are
+// used as the top-level replaces.
+func FindReplacementsInFiles(logger *logrus.Logger, main string, paths []string) (*Replacements, error) {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	replacements := &Replacements{
+		main:            main,
+		topLevel:        map[string]Replacement{},
+		originToReplace: map[string][]Replacement{},
+	}
+
+	var found []Replacement
+	for _, path := range paths {
+		logger.Debugf("Parsing go.mod at %q.", path)
+		rawGoMod, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read go.mod file %q", path)
+		}
+		module := &depgraph.Module{GoMod: path}
+		goMod := parseGoModContent(logger, module, string(rawGoMod))
+		if goMod.Module == "" {
+			return nil, fmt.Errorf("go.mod file %q does not contain a module directive", path)
+		}
+		module.Main, module.Path = goMod.Module == main, goMod.Module
+
+		replaces := replacementsOf(logger, module, goMod)
+		replacements.recordDuplicates(module.Path, replaces)
+		if module.Main {
+			for _, replace := range replaces {
+				replacements.topLevel[replace.Original] = replace
 			}
-			replacements.originToReplace[replace.Original] = append(replaces, replace)
+		} else {
+			found = append(found, replaces...)
 		}
+		replacements.exclusions = append(replacements.exclusions, exclusionsOf(logger, module, goMod)...)
 	}
-	sort.Strings(replacements.replacedModules)
-	for origin, replaces := range replacements.originToReplace {
+	replacements.index(found)
+	return replacements, nil
+}
+
+// index groups the given replacements by the module they replace and orders both the replacements
+// and exclusions.
+func (r *Replacements) index(found []Replacement) {
+	for _, replace := range found {
+		replaces, ok := r.originToReplace[replace.Original]
+		if !ok {
+			r.replacedModules = append(r.replacedModules, replace.Original)
+		}
+		r.originToReplace[replace.Original] = append(replaces, replace)
+	}
+	sort.Strings(r.replacedModules)
+	sortExclusions(r.exclusions)
+	for origin, replaces := range r.originToReplace {
 		sort.Sort(orderedReplacements(replaces))
-		replacements.originToReplace[origin] = replaces
+		r.originToReplace[origin] = replaces
 	}
-	return replacements, nil
 }
 
-func parseGoMod(
+// FindMultiModuleReplacements runs FindReplacementsWithOptions for each of the given graphs, for
+// example for each of the independent modules of a repository. The results are keyed by the path of
+// the main module of the graph in which they were found. An error is returned if several graphs
+// share the same main module.
+func FindMultiModuleReplacements(
+	logger *logrus.Logger,
+	graphs []*depgraph.DepGraph,
+	opts FindOptions,
+) (map[string]*Replacements, error) {
+	results := make(map[string]*Replacements, len(graphs))
+	for _, graph := range graphs {
+		main := graph.Main().Name()
+		if _, ok := results[main]; ok {
+			return nil, fmt.Errorf("found multiple dependency graphs for main module %q", main)
+		}
+		replacements, err := FindReplacementsWithOptions(logger, graph, opts)
+		if err != nil {
+			return nil, err
+		}
+		results[main] = replacements
+	}
+	return results, nil
+}
+
+// Main returns the path of the main module for which the replacements were found.
+func (r *Replacements) Main() string {
+	return r.main
+}
+
+// resolveChains follows the replacements whose override is itself the original of another
+// replacement. A top-level replace of an override takes precedence over the ones in other go.mod
+// files. Chains that loop back onto themselves are cut at the first repeated module.
+func (r *Replacements) resolveChains(logger *logrus.Logger) {
+	next := func(module string) (string, bool) {
+		if topLevel, ok := r.topLevel[module]; ok {
+			return topLevel.Override, true
+		}
+		if replaces := r.originToReplace[module]; len(replaces) > 0 {
+			return replaces[0].Override, true
+		}
+		return "", false
+	}
+
+	for _, origin := range r.replacedModules {
+		for idx := range r.originToReplace[origin] {
+			replacement := &r.originToReplace[origin][idx]
+			visited := map[string]struct{}{replacement.Original: {}, replacement.Override: {}}
+			current := replacement.Override
+			for {
+				override, ok := next(current)
+				if !ok {
+					break
+				}
+				if _, ok = visited[override]; ok {
+					logger.Warnf("Found a replace cycle for %q via %q.", replacement.Original, override)
+					break
+				}
+				visited[override] = struct{}{}
+				replacement.ChainDepth++
+				current = override
+			}
+			if replacement.ChainDepth > 0 {
+				replacement.Resolved = current
+			}
+		}
+	}
+}
+
+// parseGoMod returns the replace and exclude directives of the go.mod file of the specified module.
+// If the module is the main module, the replaces of modules that are already replaced at the
+// top-level are omitted. Modules that are replaced more than once are recorded as duplicates.
+func (r *Replacements) parseGoMod(
 	logger *logrus.Logger,
 	topLevelModule *depgraph.Module,
-	topLevelReplaces map[string]string,
 	module *depgraph.Module,
-) ([]Replacement, error) {
+) ([]Replacement, []Exclusion, error) {
 	module, goModPath := findGoModFile(logger, module)
 	if goModPath == "" {
 		logger.Debugf("Skipping %q as no go.mod file was found.", module.Path)
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	logger.Debugf("Parsing go.mod for %q at %q.", module.Path, goModPath)
 	rawGoMod, err := ioutil.ReadFile(goModPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read your module's go.mod file %q", goModPath)
+		return nil, nil, fmt.Errorf("failed to read your module's go.mod file %q", goModPath)
 	}
 
-	replaces := parseGoModForReplacements(logger, module, string(rawGoMod))
+	goMod := parseGoModContent(logger, module, string(rawGoMod))
+	replaces := replacementsOf(logger, module, goMod)
+	exclusions := exclusionsOf(logger, module, goMod)
+	r.recordDuplicates(module.Path, replaces)
 	if module.Path == topLevelModule.Path {
 		logger.Debugf(
 			"Auto-dependency on %q detected at version %q. Filtering already known top-level dependencies.",
@@ -236,15 +780,126 @@ func parseGoMod(
 		)
 		var filteredReplaces []Replacement
 		for _, replace := range replaces {
-			if _, ok := topLevelReplaces[replace.Original]; !ok {
+			if _, ok := r.topLevel[replace.Original]; !ok {
 				filteredReplaces = append(filteredReplaces, replace)
 			}
 		}
 		replaces = filteredReplaces
 	}
+	return replaces, exclusions, nil
+}
+
+// parseGoWork returns the replace directives of the go.work file that applies to the main module,
+// if there is one. As a workspace's replaces take precedence over those of the main module they are
+// treated as top-level replaces as well.
+func parseGoWork(logger *logrus.Logger, mainModule *depgraph.Module) ([]Replacement, error) {
+	goWorkPath := findGoWorkFile(logger, mainModule)
+	if goWorkPath == "" {
+		return nil, nil
+	}
+	rawGoWork, err := ioutil.ReadFile(goWorkPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read your workspace's go.work file %q", goWorkPath)
+	}
+
+	logger.Debugf("Parsing go.work at %q.", goWorkPath)
+	return parseGoModForReplacements(logger, mainModule, string(rawGoWork)), nil
+}
+
+// findGoWorkFile returns the path of the go.work file that applies to the main module, or an empty
+// string if there is none. Like 'go' it honours the GOWORK environment variable and otherwise looks
+// for a go.work file in the directory of the main module's go.mod and in each of its parents.
+func findGoWorkFile(logger *logrus.Logger, mainModule *depgraph.Module) string {
+	if goWork := os.Getenv("GOWORK"); goWork != "" {
+		if goWork == "off" {
+			logger.Debug("Workspaces are disabled via GOWORK.")
+			return ""
+		}
+		return goWork
+	}
+	if mainModule.GoMod == "" {
+		return ""
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(mainModule.GoMod))
+	if err != nil {
+		dir = filepath.Dir(mainModule.GoMod)
+	}
+	for {
+		goWorkPath := filepath.Join(dir, "go.work")
+		if _, err = os.Stat(goWorkPath); err == nil {
+			return goWorkPath
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			logger.Debugf("No go.work file found for %q.", mainModule.Path)
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// parseVendoredGoMods returns the replace directives of all go.mod files that are present inside
+// the vendor directory of the main module. The offender of each replacement is the corresponding
+// module of the graph if it is part of it. Modules that are replaced more than once by a file are
+// recorded as duplicates.
+func (r *Replacements) parseVendoredGoMods(logger *logrus.Logger, graph *depgraph.DepGraph) ([]Replacement, error) {
+	vendorDir, paths, err := vendoredGoModFiles(logger, graph.Main().Module)
+	if err != nil {
+		return nil, err
+	}
+
+	var replaces []Replacement
+	for _, path := range paths {
+		relDir, err := filepath.Rel(vendorDir, filepath.Dir(path))
+		if err != nil {
+			return nil, err
+		}
+		module := &depgraph.Module{Path: filepath.ToSlash(relDir), GoMod: path}
+		if node := graph.Node(module.Path); node != nil {
+			module = node.Module
+		}
+
+		logger.Debugf("Parsing vendored go.mod for %q at %q.", module.Path, path)
+		rawGoMod, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vendored go.mod file %q", path)
+		}
+		vendoredReplaces := parseGoModForReplacements(logger, module, string(rawGoMod))
+		r.recordDuplicates(module.Path, vendoredReplaces)
+		for _, replace := range vendoredReplaces {
+			replace.Vendored = true
+			replaces = append(replaces, replace)
+		}
+	}
 	return replaces, nil
 }
 
+// vendoredGoModFiles returns the vendor directory of the main module together with the paths of
+// all go.mod files that are present inside of it. Both are empty if there is no such directory.
+func vendoredGoModFiles(logger *logrus.Logger, mainModule *depgraph.Module) (string, []string, error) {
+	if mainModule.GoMod == "" {
+		return "", nil, nil
+	}
+	vendorDir := filepath.Join(filepath.Dir(mainModule.GoMod), "vendor")
+	if _, err := os.Stat(vendorDir); err != nil {
+		logger.Debugf("No vendor directory found at %q.", vendorDir)
+		return "", nil, nil
+	}
+
+	var paths []string
+	err := filepath.Walk(vendorDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && info.Name() == "go.mod" {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return vendorDir, paths, nil
+}
+
 func findGoModFile(logger *logrus.Logger, module *depgraph.Module) (*depgraph.Module, string) {
 	if module == nil {
 		return nil, ""
@@ -265,38 +920,102 @@ func findGoModFile(logger *logrus.Logger, module *depgraph.Module) (*depgraph.Mo
 }
 
 func parseGoModForReplacements(logger *logrus.Logger, module *depgraph.Module, goModContent string) []Replacement {
-	var replacements []Replacement
-	for _, singleReplaceMatch := range singleReplaceRE.FindAllStringSubmatch(goModContent, -1) {
-		replacements = append(replacements, parseReplacements(logger, module, singleReplaceMatch[1])...)
-	}
-	for _, multiReplaceMatch := range multiReplaceRE.FindAllStringSubmatch(goModContent, -1) {
-		replacements = append(replacements, parseReplacements(logger, module, multiReplaceMatch[1])...)
+	return replacementsOf(logger, module, parseGoModContent(logger, module, goModContent))
+}
+
+// parseGoModContent parses the content of the go.mod file of the specified module. Invalid
+// directives are logged and skipped.
+func parseGoModContent(logger *logrus.Logger, module *depgraph.Module, goModContent string) *modfile.File {
+	goMod, err := modfile.Parse(goModContent)
+	if err != nil {
+		logger.WithError(err).Warnf("Skipping invalid directives in the go.mod of %q.", module.Path)
 	}
-	return replacements
+	return goMod
 }
 
-func parseReplacements(logger *logrus.Logger, module *depgraph.Module, replaceString string) []Replacement {
+// replacementsOf returns the replace directives of the parsed go.mod file of the specified module.
+func replacementsOf(logger *logrus.Logger, module *depgraph.Module, goMod *modfile.File) []Replacement {
 	var replacements []Replacement
-	for _, replaceMatch := range replaceRE.FindAllStringSubmatch(replaceString, -1) {
-		replace := Replacement{
-			Offender: module,
-			Original: replaceMatch[1],
-			Override: replaceMatch[2],
-			Version:  replaceMatch[3],
+	for _, replace := range goMod.Replaces {
+		replacement := Replacement{
+			Offender:        module,
+			Original:        replace.Original,
+			OriginalVersion: replace.OriginalVersion,
+			Override:        replace.Override,
+			Version:         replace.Version,
+			Line:            replace.Line,
 		}
 		logger.Debugf(
 			"Found hidden replace of %q by %q in dependency %q.",
-			replace.Original,
-			replace.Override,
-			replace.Offender.Path,
+			replacement.Original,
+			replacement.Override,
+			replacement.Offender.Path,
 		)
-		replacements = append(replacements, replace)
+		replacements = append(replacements, replacement)
+	}
+	for _, original := range duplicateOriginals(replacements) {
+		logger.Warnf("The go.mod of %q replaces %q more than once. This will be rejected by 'go'.", module.Path, original)
 	}
 	return replacements
 }
 
+// recordDuplicates records the modules that are replaced more than once by the given replacements
+// of a single go.mod file of the specified offender.
+func (r *Replacements) recordDuplicates(offender string, replacements []Replacement) {
+	for _, original := range duplicateOriginals(replacements) {
+		if r.duplicates == nil {
+			r.duplicates = map[string][]string{}
+		}
+		if !containsString(r.duplicates[offender], original) {
+			r.duplicates[offender] = append(r.duplicates[offender], original)
+			sort.Strings(r.duplicates[offender])
+		}
+	}
+}
+
+// filterDuplicates returns the recorded duplicate replaces for which 'keep' returns true.
+func (r *Replacements) filterDuplicates(keep func(offender string, original string) bool) map[string][]string {
+	var filtered map[string][]string
+	for offender, originals := range r.duplicates {
+		for _, original := range originals {
+			if keep(offender, original) {
+				if filtered == nil {
+					filtered = map[string][]string{}
+				}
+				filtered[offender] = append(filtered[offender], original)
+			}
+		}
+	}
+	return filtered
+}
+
+// duplicateOriginals returns the sorted modules that are replaced several times by the given
+// replacements, which are expected to stem from a single go.mod file. Replacements that are
+// restricted to distinct versions of the same module are not duplicates of each other.
+func duplicateOriginals(replacements []Replacement) []string {
+	seen := map[string]int{}
+	var duplicates []string
+	for _, replacement := range replacements {
+		original := replacement.Original
+		if replacement.OriginalVersion != "" {
+			original += "@" + replacement.OriginalVersion
+		}
+		seen[original]++
+		if seen[original] == 2 {
+			duplicates = append(duplicates, original)
+		}
+	}
+	sort.Strings(duplicates)
+	return duplicates
+}
+
 type orderedReplacements []Replacement
 
-func (r orderedReplacements) Len() int               { return len(r) }
-func (r orderedReplacements) Swap(i int, j int)      { r[i], r[j] = r[j], r[i] }
-func (r orderedReplacements) Less(i int, j int) bool { return r[i].Offender.Path < r[j].Offender.Path }
+func (r orderedReplacements) Len() int          { return len(r) }
+func (r orderedReplacements) Swap(i int, j int) { r[i], r[j] = r[j], r[i] }
+func (r orderedReplacements) Less(i int, j int) bool {
+	if r[i].Offender.Path != r[j].Offender.Path {
+		return r[i].Offender.Path < r[j].Offender.Path
+	}
+	return !r[i].Vendored && r[j].Vendored
+}
@@ -2,12 +2,14 @@ package reveal
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Helcaraxan/gomod/lib/depgraph"
 )
@@ -47,12 +49,25 @@ var (
 		Override: "overrideB-bis",
 		Version:  "v2.0.0",
 	}
+	replaceG = Replacement{
+		Offender:        moduleA,
+		Original:        "originalB",
+		OriginalVersion: "v0.4.3",
+		Override:        "overrideB",
+		Version:         "v1.0.0",
+	}
+	replaceH = Replacement{
+		Offender:        moduleA,
+		Original:        "originalD",
+		OriginalVersion: "v0.1.0",
+		Override:        "./overrideD",
+	}
 
 	testReplacements = &Replacements{
 		main: "test-module",
-		topLevel: map[string]string{
-			"originalA": "overrideA",
-			"originalB": "overrideB-bis",
+		topLevel: map[string]Replacement{
+			"originalA": {Original: "originalA", Override: "overrideA"},
+			"originalB": {Original: "originalB", Override: "overrideB-bis"},
 		},
 		replacedModules: []string{
 			"originalA",
@@ -109,6 +124,12 @@ func init() {
 	testGraph.AddNode(moduleD)
 }
 
+// atLine returns a copy of the replacement that was found at the specified line.
+func atLine(replacement Replacement, line int) Replacement {
+	replacement.Line = line
+	return replacement
+}
+
 func Test_ParseReplaces(t *testing.T) {
 	logger := logrus.New()
 
@@ -120,7 +141,7 @@ func Test_ParseReplaces(t *testing.T) {
 		"SingleReplace": {
 			input:    "replace originalA => overrideA v1.0.0",
 			offender: &depgraph.Module{Path: "offender"},
-			expected: []Replacement{replaceA},
+			expected: []Replacement{atLine(replaceA, 1)},
 		},
 		"MultiReplace": {
 			input: `
@@ -131,8 +152,8 @@ replace (
 `,
 			offender: moduleA,
 			expected: []Replacement{
-				replaceB,
-				replaceC,
+				atLine(replaceB, 3),
+				atLine(replaceC, 4),
 			},
 		},
 		"MixedReplace": {
@@ -146,9 +167,9 @@ replace originalD => ./overrideD
 `,
 			offender: moduleA,
 			expected: []Replacement{
-				replaceD,
-				replaceB,
-				replaceC,
+				atLine(replaceB, 3),
+				atLine(replaceC, 4),
+				atLine(replaceD, 7),
 			},
 		},
 		"FullGoMod": {
@@ -176,9 +197,88 @@ replace (
 `,
 			offender: moduleA,
 			expected: []Replacement{
-				replaceC,
-				replaceB,
-				replaceD,
+				atLine(replaceC, 14),
+				atLine(replaceB, 19),
+				atLine(replaceD, 20),
+			},
+		},
+		"CommentedReplace": {
+			input: `
+// Previously we used: replace originalA => overrideA v0.1.0
+replace originalC => ./overrideC // Migrated originalC => ./overrideC-old (see #42).
+
+replace (
+	// Temporary fix (until upstream => v1.1.0 is released).
+	originalB => overrideB v1.0.0 // Was: originalB => overrideB v0.9.0
+	originalD => ./overrideD
+)
+`,
+			offender: moduleA,
+			expected: []Replacement{
+				atLine(replaceC, 3),
+				atLine(replaceB, 7),
+				atLine(replaceD, 8),
+			},
+		},
+		"VersionedMultiReplace": {
+			input: `
+replace (
+	originalB v0.4.3 => overrideB v1.0.0
+	originalC => ./overrideC
+	originalD v0.1.0 => ./overrideD
+)
+`,
+			offender: moduleA,
+			expected: []Replacement{
+				atLine(replaceG, 3),
+				atLine(replaceC, 4),
+				atLine(replaceH, 5),
+			},
+		},
+		"VersionedFullGoMod": {
+			input: `module github.com/foo/bar
+
+go = 1.12.5
+
+require (
+	github.com/my-dep/A v1.2.0
+	originalB v0.4.3
+	originalD v0.1.0
+)
+
+replace originalD v0.1.0 => ./overrideD // Bar
+
+replace (
+	// Foo.
+	originalB v0.4.3 => overrideB v1.0.0
+)
+`,
+			offender: moduleA,
+			expected: []Replacement{
+				atLine(replaceH, 11),
+				atLine(replaceG, 15),
+			},
+		},
+		"CRLFLineEndings": {
+			input:    "module moduleA\r\n\r\nreplace originalC => ./overrideC // Bar\r\n\r\nreplace (\r\n\toriginalB v0.4.3 => overrideB v1.0.0\r\n\toriginalD => ./overrideD\r\n)\r\n",
+			offender: moduleA,
+			expected: []Replacement{
+				atLine(replaceC, 3),
+				atLine(replaceG, 6),
+				atLine(replaceD, 7),
+			},
+		},
+		"ByteOrderMark": {
+			input:    "\ufeffreplace originalA => overrideA v1.0.0\n",
+			offender: &depgraph.Module{Path: "offender"},
+			expected: []Replacement{atLine(replaceA, 1)},
+		},
+		"ByteOrderMarkAndCRLF": {
+			input:    "\ufeffreplace (\r\n\toriginalB => overrideB v1.0.0\r\n\toriginalC => ./overrideC\r\n)\r\n",
+			offender: moduleA,
+			expected: []Replacement{
+				atLine(replaceB, 2),
+				atLine(replaceC, 3),
 			},
 		},
 	}
@@ -196,17 +296,25 @@ func Test_FindReplacements(t *testing.T) {
 	logger.SetOutput(ioutil.Discard)
 
 	expectedReplacements := &Replacements{
-		main:     "test/module",
-		topLevel: map[string]string{"module/foo": "module/foo-bis"},
+		main: "test/module",
+		topLevel: map[string]Replacement{
+			"module/foo": {Offender: testGraph.Main().Module, Original: "module/foo", Override: "module/foo-ter", Version: "v1.1.0", Line: 6},
+			"originalB":  {Offender: testGraph.Main().Module, Original: "originalB", Override: "overrideB", Version: "v1.0.0", Line: 7},
+		},
 		replacedModules: []string{
 			"originalB",
 			"originalC",
 			"originalD",
 		},
 		originToReplace: map[string][]Replacement{
-			"originalB": {replaceB},
-			"originalC": {replaceC},
-			"originalD": {replaceD},
+			"originalB": {atLine(replaceB, 14)},
+			"originalC": {atLine(replaceC, 15)},
+			"originalD": {atLine(replaceD, 11)},
+		},
+		exclusions: []Exclusion{
+			{Offender: testGraph.Main().Module, Module: "module/bar", Version: "v1.0.1"},
+			{Offender: moduleA, Module: "originalB", Version: "v0.9.0"},
+			{Offender: testGraph.Main().Module, Module: "originalB", Version: "v0.9.1"},
 		},
 	}
 
@@ -215,6 +323,183 @@ func Test_FindReplacements(t *testing.T) {
 	assert.Equal(t, expectedReplacements, replacements, "Should find the expected replacement information.")
 }
 
+func Test_FindReplacementsInFiles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	mainGoMod := filepath.Join("testdata", "mainModule", "go.mod")
+	moduleAGoMod := filepath.Join("testdata", "moduleA", "go.mod")
+	mainModule := &depgraph.Module{Main: true, Path: "test/module", GoMod: mainGoMod}
+	offender := &depgraph.Module{Path: "moduleA", GoMod: moduleAGoMod}
+	inFile := func(replacement Replacement, line int) Replacement {
+		replacement.Offender = offender
+		return atLine(replacement, line)
+	}
+
+	replacements, err := FindReplacementsInFiles(
+		logger,
+		"test/module",
+		[]string{mainGoMod, moduleAGoMod, filepath.Join("testdata", "moduleB", "go.mod")},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &Replacements{
+		main: "test/module",
+		topLevel: map[string]Replacement{
+			"module/foo": {Offender: mainModule, Original: "module/foo", Override: "module/foo-bis", Version: "v1.0.0", Line: 10},
+		},
+		replacedModules: []string{"originalB", "originalC", "originalD"},
+		originToReplace: map[string][]Replacement{
+			"originalB": {inFile(replaceB, 14)},
+			"originalC": {inFile(replaceC, 15)},
+			"originalD": {inFile(replaceD, 11)},
+		},
+		exclusions: []Exclusion{
+			{Offender: mainModule, Module: "module/bar", Version: "v1.0.1"},
+			{Offender: offender, Module: "originalB", Version: "v0.9.0"},
+			{Offender: mainModule, Module: "originalB", Version: "v0.9.1"},
+		},
+	}, replacements)
+
+	replacements, err = FindReplacementsInFiles(logger, "test/module", []string{filepath.Join("testdata", "windowsModule", "go.mod")})
+	require.NoError(t, err, "Should find the module directive after a byte order mark.")
+	if assert.Len(t, replacements.originToReplace["originalC"], 1) {
+		assert.Equal(t, "moduleW", replacements.originToReplace["originalC"][0].Offender.Path)
+		assert.Equal(t, 5, replacements.originToReplace["originalC"][0].Line)
+	}
+
+	_, err = FindReplacementsInFiles(logger, "test/module", []string{filepath.Join("testdata", "missing", "go.mod")})
+	assert.Error(t, err, "Should fail on unreadable files.")
+	_, err = FindReplacementsInFiles(logger, "test/module", []string{filepath.Join("testdata", "mainModule", "go.work")})
+	assert.Error(t, err, "Should fail on files without a module directive.")
+}
+
+func Test_FindMultiModuleReplacements(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	otherGraph := depgraph.NewGraph(logger, &depgraph.Module{
+		Main:  true,
+		Path:  "moduleA",
+		GoMod: filepath.Join("testdata", "moduleA", "go.mod"),
+	})
+
+	results, err := FindMultiModuleReplacements(logger, []*depgraph.DepGraph{testGraph, otherGraph}, FindOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "test/module", results["test/module"].Main())
+	assert.Equal(t, []string{"originalB", "originalC", "originalD"}, results["test/module"].replacedModules)
+	assert.Equal(t, "moduleA", results["moduleA"].Main())
+	assert.Empty(t, results["moduleA"].replacedModules, "Should only consider the dependencies of each main module.")
+
+	_, err = FindMultiModuleReplacements(logger, []*depgraph.DepGraph{testGraph, testGraph}, FindOptions{})
+	assert.Error(t, err, "Should refuse graphs with the same main module.")
+}
+
+func Test_FindReplacementsVendored(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	replaceVendoredB := Replacement{
+		Offender: moduleA,
+		Original: "originalB",
+		Override: "overrideB",
+		Version:  "v0.9.0",
+		Vendored: true,
+		Line:     7,
+	}
+
+	replacements, err := FindReplacementsWithOptions(logger, testGraph, FindOptions{IncludeVendor: true})
+	assert.NoError(t, err, "Should not error while searching for replacements.")
+	assert.Equal(t, []string{"originalB", "originalC", "originalD"}, replacements.replacedModules)
+	assert.Equal(t, []Replacement{atLine(replaceB, 14), replaceVendoredB}, replacements.originToReplace["originalB"], "Should find vendored replacements.")
+
+	writer := &strings.Builder{}
+	assert.NoError(t, replacements.Print(logger, writer, nil, []string{"originalB"}, PrintOpts{}))
+	assert.Equal(t, `'originalB' is replaced:
+ ✓ moduleA -> overrideB @ v1.0.0 (moduleA/go.mod:14)
+ ✓ moduleA -> overrideB @ v0.9.0 (vendored) (moduleA/go.mod:7)
+
+'originalB' is excluded:
+   moduleA     @ v0.9.0
+   test/module @ v0.9.1
+
+[✓] Match with a top-level replace in 'test/module'
+`, writer.String(), "Should mark vendored replacements.")
+}
+
+func Test_ResolveChains(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	toB := Replacement{Offender: moduleA, Original: "originalA", Override: "originalB"}
+	toC := Replacement{Offender: moduleA, Original: "originalB", Override: "originalC"}
+	toD := Replacement{Offender: moduleA, Original: "originalC", Override: "overrideD"}
+	loopX := Replacement{Offender: moduleA, Original: "loopX", Override: "loopY"}
+	loopY := Replacement{Offender: moduleA, Original: "loopY", Override: "loopX"}
+	replacements := &Replacements{
+		topLevel:        map[string]Replacement{"originalC": {Original: "originalC", Override: "overrideC"}},
+		replacedModules: []string{"loopX", "loopY", "originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"loopX":     {loopX},
+			"loopY":     {loopY},
+			"originalA": {toB},
+			"originalB": {toC},
+			"originalC": {toD},
+		},
+	}
+	replacements.resolveChains(logger)
+
+	resolved := replacements.originToReplace
+	assert.Equal(t, 2, resolved["originalA"][0].ChainDepth)
+	assert.Equal(t, "overrideC", resolved["originalA"][0].Resolved, "Should prefer top-level replaces when following chains.")
+	assert.Equal(t, 1, resolved["originalB"][0].ChainDepth)
+	assert.Equal(t, "overrideC", resolved["originalB"][0].Resolved)
+	assert.Zero(t, resolved["originalC"][0].ChainDepth)
+	assert.Empty(t, resolved["originalC"][0].Resolved)
+	assert.Zero(t, resolved["loopX"][0].ChainDepth, "Should stop following a replace cycle.")
+	assert.Zero(t, resolved["loopY"][0].ChainDepth, "Should stop following a replace cycle.")
+}
+
+func Test_ParseGoWork(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	replaces, err := parseGoWork(logger, moduleA)
+	assert.NoError(t, err)
+	assert.Empty(t, replaces, "Should not find replaces without a go.work file.")
+
+	replaces, err = parseGoWork(logger, testGraph.Main().Module)
+	assert.NoError(t, err)
+	assert.Equal(t, []Replacement{
+		{Offender: testGraph.Main().Module, Original: "module/foo", Override: "module/foo-ter", Version: "v1.1.0", Line: 6},
+		{Offender: testGraph.Main().Module, Original: "originalB", Override: "overrideB", Version: "v1.0.0", Line: 7},
+	}, replaces, "Should find the workspace's replaces.")
+
+	workspaceModule := &depgraph.Module{Main: true, Path: "test/workspace/module", GoMod: filepath.Join("testdata", "workspace", "module", "go.mod")}
+	replaces, err = parseGoWork(logger, workspaceModule)
+	assert.NoError(t, err)
+	assert.Equal(t, []Replacement{
+		{Offender: workspaceModule, Original: "originalB", Override: "overrideB", Version: "v1.1.0", Line: 5},
+	}, replaces, "Should find a go.work file in a parent directory.")
+
+	goWork, isSet := os.LookupEnv("GOWORK")
+	defer func() {
+		if isSet {
+			_ = os.Setenv("GOWORK", goWork)
+		} else {
+			_ = os.Unsetenv("GOWORK")
+		}
+	}()
+	require.NoError(t, os.Setenv("GOWORK", "off"))
+	replaces, err = parseGoWork(logger, testGraph.Main().Module)
+	assert.NoError(t, err)
+	assert.Empty(t, replaces, "Should ignore go.work files when workspaces are disabled.")
+	require.NoError(t, os.Setenv("GOWORK", filepath.Join("testdata", "workspace", "go.work")))
+	replaces, err = parseGoWork(logger, testGraph.Main().Module)
+	assert.NoError(t, err)
+	assert.Len(t, replaces, 1, "Should use the go.work file specified via GOWORK.")
+}
+
 func Test_FilterReplacements(t *testing.T) {
 	t.Run("OffenderEmpty", func(t *testing.T) {
 		filtered := testReplacements.FilterOnOffendingModule(nil)
@@ -224,9 +509,9 @@ func Test_FilterReplacements(t *testing.T) {
 		filtered := testReplacements.FilterOnOffendingModule([]string{"offender", "pre-offender", "offender-post"})
 		assert.Equal(t, &Replacements{
 			main: "test-module",
-			topLevel: map[string]string{
-				"originalA": "overrideA",
-				"originalB": "overrideB-bis",
+			topLevel: map[string]Replacement{
+				"originalA": {Original: "originalA", Override: "overrideA"},
+				"originalB": {Original: "originalB", Override: "overrideB-bis"},
 			},
 			replacedModules: []string{
 				"originalA",
@@ -245,9 +530,9 @@ func Test_FilterReplacements(t *testing.T) {
 		filtered := testReplacements.FilterOnReplacedModule([]string{"originalA", "originalC", "not-original"})
 		assert.Equal(t, &Replacements{
 			main: "test-module",
-			topLevel: map[string]string{
-				"originalA": "overrideA",
-				"originalB": "overrideB-bis",
+			topLevel: map[string]Replacement{
+				"originalA": {Original: "originalA", Override: "overrideA"},
+				"originalB": {Original: "originalB", Override: "overrideB-bis"},
 			},
 			replacedModules: []string{
 				"originalA",
@@ -259,6 +544,59 @@ func Test_FilterReplacements(t *testing.T) {
 			},
 		}, filtered, "Should filter out the expected replacements.")
 	})
+
+	t.Run("OverridesEmpty", func(t *testing.T) {
+		filtered := testReplacements.FilterOnOverride(nil)
+		assert.Equal(t, testReplacements, filtered, "Should return an identical array.")
+	})
+	t.Run("Overrides", func(t *testing.T) {
+		filtered := testReplacements.FilterOnOverride([]string{"overrideA-bis", "./overrideC", "not-override"})
+		assert.Equal(t, &Replacements{
+			main: "test-module",
+			topLevel: map[string]Replacement{
+				"originalA": {Original: "originalA", Override: "overrideA"},
+				"originalB": {Original: "originalB", Override: "overrideB-bis"},
+			},
+			replacedModules: []string{
+				"originalA",
+				"originalC",
+			},
+			originToReplace: map[string][]Replacement{
+				"originalA": {replaceE},
+				"originalC": {replaceC},
+			},
+		}, filtered, "Should filter out the expected replacements.")
+	})
+}
+
+func Test_FilterNonMain(t *testing.T) {
+	replacements := &Replacements{
+		main:            "moduleA",
+		topLevel:        map[string]Replacement{"originalB": {Original: "originalB", Override: "overrideB"}},
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, replaceE},
+			"originalB": {replaceB, replaceF},
+			"originalC": {replaceC},
+		},
+	}
+	assert.Equal(t, &Replacements{
+		main:            "moduleA",
+		topLevel:        map[string]Replacement{"originalB": {Original: "originalB", Override: "overrideB"}},
+		replacedModules: []string{"originalA"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, replaceE},
+		},
+	}, replacements.FilterNonMain(), "Should filter out the modules replaced by the main module as well as its own replacements.")
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	replacements, err := FindReplacements(logger, testGraph)
+	require.NoError(t, err)
+	filtered := replacements.FilterNonMain()
+	assert.Equal(t, []string{"originalC", "originalD"}, filtered.replacedModules, "Should drop the modules replaced by the main module.")
+	assert.Equal(t, replacements.topLevel, filtered.topLevel, "Should retain the top-level replaces.")
 }
 
 func Test_PrintReplacements(t *testing.T) {
@@ -280,10 +618,247 @@ func Test_PrintReplacements(t *testing.T) {
 	logger.SetOutput(ioutil.Discard)
 
 	writer := &strings.Builder{}
-	testReplacements.Print(logger, writer, nil, nil)
+	testReplacements.Print(logger, writer, nil, nil, PrintOpts{})
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+}
+
+func Test_PrintReplacementsByOffender(t *testing.T) {
+	const expectedOutput = `'moduleA' replaces:
+   originalB -> overrideB   @ v1.0.0
+   originalC -> ./overrideC
+
+'offender' replaces:
+ ✓ originalA -> overrideA @ v1.0.0
+
+'offender-bis' replaces:
+   originalA -> overrideA-bis @ v2.0.0
+
+'offender-tertio' replaces:
+ ✓ originalB -> overrideB-bis @ v2.0.0
+
+[✓] Match with a top-level replace in 'test-module'
+`
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	writer := &strings.Builder{}
+	testReplacements.PrintByOffender(logger, writer, nil, nil, PrintOpts{})
 	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
 }
 
+func Test_PrintReplacementsGroupByMatch(t *testing.T) {
+	const expectedOutput = `Replacements without a matching top-level replace:
+
+'originalA' is replaced:
+   offender-bis -> overrideA-bis @ v2.0.0
+
+'originalB' is replaced:
+   moduleA -> overrideB @ v1.0.0
+
+'originalC' is replaced:
+   moduleA -> ./overrideC
+
+Replacements with a matching top-level replace:
+
+'originalA' is replaced:
+ ✓ offender -> overrideA @ v1.0.0
+
+'originalB' is replaced:
+ ✓ offender-tertio -> overrideB-bis @ v2.0.0
+
+[✓] Match with a top-level replace in 'test-module'
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testReplacements.Print(nil, writer, nil, nil, PrintOpts{GroupByMatch: true}))
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+
+	const expectedByOffender = `Replacements without a matching top-level replace:
+
+'moduleA' replaces:
+   originalB -> overrideB   @ v1.0.0
+   originalC -> ./overrideC
+
+'offender-bis' replaces:
+   originalA -> overrideA-bis @ v2.0.0
+
+Replacements with a matching top-level replace:
+
+'offender' replaces:
+ ✓ originalA -> overrideA @ v1.0.0
+
+'offender-tertio' replaces:
+ ✓ originalB -> overrideB-bis @ v2.0.0
+
+[✓] Match with a top-level replace in 'test-module'
+`
+
+	writer = &strings.Builder{}
+	assert.NoError(t, testReplacements.PrintByOffender(nil, writer, nil, nil, PrintOpts{GroupByMatch: true}))
+	assert.Equal(t, expectedByOffender, writer.String(), "Should print the expected output.")
+}
+
+func Test_PrintReplacementsLocation(t *testing.T) {
+	replacements := &Replacements{
+		main:            "test-module",
+		replacedModules: []string{"originalC"},
+		originToReplace: map[string][]Replacement{"originalC": {atLine(replaceC, 15)}},
+	}
+
+	writer := &strings.Builder{}
+	assert.NoError(t, replacements.Print(nil, writer, nil, nil, PrintOpts{}))
+	assert.Equal(t, "'originalC' is replaced:\n   moduleA -> ./overrideC (moduleA/go.mod:15)\n\n", writer.String())
+}
+
+func Test_PrintReplacementsReplacedOffender(t *testing.T) {
+	replaceModuleA := Replacement{
+		Offender: &depgraph.Module{Path: "offender"},
+		Original: "moduleA",
+		Override: "moduleA-fork",
+		Version:  "v1.0.0",
+	}
+	replacements := &Replacements{
+		main:            "test-module",
+		replacedModules: []string{"moduleA", "originalB"},
+		originToReplace: map[string][]Replacement{
+			"moduleA":   {replaceModuleA},
+			"originalB": {replaceB},
+		},
+	}
+
+	const expectedOutput = `'moduleA' is replaced:
+   offender -> moduleA-fork @ v1.0.0
+
+'originalB' is replaced:
+   moduleA -> overrideB @ v1.0.0 (offender is replaced)
+
+`
+	writer := &strings.Builder{}
+	assert.NoError(t, replacements.Print(nil, writer, nil, nil, PrintOpts{}))
+	assert.Equal(t, expectedOutput, writer.String(), "Should mark offenders that are themselves replaced.")
+
+	replacements = &Replacements{
+		main:            "test-module",
+		topLevel:        map[string]Replacement{"moduleA": {Original: "moduleA", Override: "moduleA-fork", Version: "v1.0.0"}},
+		replacedModules: []string{"originalB"},
+		originToReplace: map[string][]Replacement{"originalB": {replaceB}},
+	}
+	writer.Reset()
+	assert.NoError(t, replacements.Print(nil, writer, nil, nil, PrintOpts{}))
+	assert.Equal(
+		t,
+		"'originalB' is replaced:\n   moduleA -> overrideB @ v1.0.0 (offender is replaced)\n\n",
+		writer.String(),
+		"Should mark offenders that are replaced by the main module.",
+	)
+}
+
+func Test_PrintReplacementsColor(t *testing.T) {
+	const expectedOutput = "'originalA' is replaced:\n" +
+		"\x1b[32m ✓ offender     -> overrideA     @ v1.0.0\x1b[0m\n" +
+		"\x1b[31m   offender-bis -> overrideA-bis @ v2.0.0\x1b[0m\n" +
+		"\n" +
+		"'originalB' is replaced:\n" +
+		"\x1b[31m   moduleA         -> overrideB     @ v1.0.0\x1b[0m\n" +
+		"\x1b[32m ✓ offender-tertio -> overrideB-bis @ v2.0.0\x1b[0m\n" +
+		"\n" +
+		"'originalC' is replaced:\n" +
+		"\x1b[33m   moduleA -> ./overrideC\x1b[0m\n" +
+		"\n" +
+		"[✓] Match with a top-level replace in 'test-module'\n"
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testReplacements.Print(nil, writer, nil, nil, PrintOpts{Color: true}))
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+}
+
+func Test_PrintReplacementsTree(t *testing.T) {
+	const expectedOutput = `originalA ✓
+├── offender -> overrideA @ v1.0.0
+└── offender-bis -> overrideA-bis @ v2.0.0
+
+originalB ✓
+├── moduleA -> overrideB @ v1.0.0
+└── offender-tertio -> overrideB-bis @ v2.0.0
+
+originalC
+└── moduleA -> ./overrideC
+
+[✓] Match with a top-level replace in 'test-module'
+`
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testReplacements.PrintTree(writer))
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+}
+
+func Test_PrintReplacementsTSV(t *testing.T) {
+	const expectedOutput = "originalA\toffender\toverrideA\tv1.0.0\ttrue\n" +
+		"originalA\toffender-bis\toverrideA-bis\tv2.0.0\tfalse\n" +
+		"originalB\tmoduleA\toverrideB\tv1.0.0\tfalse\n" +
+		"originalB\toffender-tertio\toverrideB-bis\tv2.0.0\ttrue\n" +
+		"originalC\tmoduleA\t./overrideC\t\tfalse\n"
+
+	writer := &strings.Builder{}
+	assert.NoError(t, testReplacements.PrintTSV(writer))
+	assert.Equal(t, expectedOutput, writer.String(), "Should print the expected output.")
+}
+
+func Test_Deduplicate(t *testing.T) {
+	duplicateA := replaceA
+	duplicateA.Offender = replaceE.Offender
+	replacements := &Replacements{
+		main:            "test-module",
+		topLevel:        map[string]Replacement{"originalA": {Original: "originalA", Override: "overrideA"}},
+		replacedModules: []string{"originalA", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {replaceA, duplicateA, replaceE},
+			"originalC": {replaceC},
+		},
+	}
+
+	deduplicated := replacements.Deduplicate()
+	expectedA := replaceA
+	expectedA.Offenders = []*depgraph.Module{replaceA.Offender, replaceE.Offender}
+	expectedE := replaceE
+	expectedE.Offenders = []*depgraph.Module{replaceE.Offender}
+	expectedC := replaceC
+	expectedC.Offenders = []*depgraph.Module{replaceC.Offender}
+	assert.Equal(t, map[string][]Replacement{
+		"originalA": {expectedA, expectedE},
+		"originalC": {expectedC},
+	}, deduplicated.originToReplace, "Should collapse identical replacements.")
+	assert.Len(t, replacements.originToReplace["originalA"], 3, "Should not have modified the original replacements.")
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	writer := &strings.Builder{}
+	assert.NoError(t, deduplicated.Print(logger, writer, nil, []string{"originalA"}, PrintOpts{}))
+	assert.Equal(t, `'originalA' is replaced:
+ ✓ offender, offender-bis -> overrideA     @ v1.0.0
+   offender-bis           -> overrideA-bis @ v2.0.0
+
+[✓] Match with a top-level replace in 'test-module'
+`, writer.String(), "Should list all offenders of a replacement.")
+
+	filtered := deduplicated.FilterOnOffendingModule([]string{"offender-bis"})
+	assert.Equal(t, []Replacement{expectedA, expectedE}, filtered.originToReplace["originalA"], "Should match offenders that are not the first one.")
+
+	writer.Reset()
+	assert.NoError(t, deduplicated.PrintByOffender(logger, writer, nil, []string{"originalA"}, PrintOpts{}))
+	assert.Equal(t, `'offender' replaces:
+ ✓ originalA -> overrideA @ v1.0.0
+
+'offender-bis' replaces:
+ ✓ originalA -> overrideA     @ v1.0.0
+   originalA -> overrideA-bis @ v2.0.0
+
+[✓] Match with a top-level replace in 'test-module'
+`, writer.String(), "Should list a replacement for each of its offenders.")
+}
+
 func Test_FindGoModFile(t *testing.T) {
 	logger := logrus.New()
 	logger.SetOutput(ioutil.Discard)
@@ -0,0 +1,204 @@
+package reveal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRules are the rules under which the replacements are reported by WriteSARIF.
+var sarifRules = []sarifRule{
+	{ID: "unmatched-top-level", ShortDescription: sarifMessage{Text: "Replace directive that is not matched by the main module."}},
+	{ID: "conflicting-replaces", ShortDescription: sarifMessage{Text: "Replace directives that disagree on the override of a module."}},
+	{ID: "stale-replace", ShortDescription: sarifMessage{Text: "Replace directive whose override is not newer than the selected version."}},
+}
+
+// WriteSARIF writes the problematic replacements in SARIF format to the specified writer so that
+// they can be consumed by code scanning tools. Replacements that are not matched by a top-level
+// replace, conflicting replacements and, if a dependency graph is given, stale replacements are
+// each reported under a distinct rule. Results are located at the replace directive in the go.mod
+// file of the offender, relative to the 'root' directory, when that file is known and lies within
+// 'root'. As code scanning tools resolve these locations against the root of the repository, see
+// SourceRoot to determine 'root'. Other results, such as those for go.mod files in the module cache,
+// have no location.
+func (r *Replacements) WriteSARIF(w io.Writer, graph *depgraph.DepGraph, root string) error {
+	var results []sarifResult
+	for _, replace := range r.UnmatchedTopLevel() {
+		results = append(results, sarifReplaceResult(root, "unmatched-top-level", "warning", replace, fmt.Sprintf(
+			"%s replaces %s but %s does not.",
+			replace.Offender.Path,
+			replace.Original,
+			r.main,
+		)))
+	}
+	conflicts := r.Conflicts()
+	for _, original := range r.replacedModules {
+		for _, replace := range conflicts[original] {
+			results = append(results, sarifReplaceResult(root, "conflicting-replaces", "error", replace, fmt.Sprintf(
+				"%s replaces %s with %s which conflicts with the replaces of other modules.",
+				replace.Offender.Path,
+				replace.Original,
+				sarifOverride(replace),
+			)))
+		}
+	}
+	if graph != nil {
+		for _, replace := range r.StaleAgainst(graph) {
+			results = append(results, sarifReplaceResult(root, "stale-replace", "note", replace, fmt.Sprintf(
+				"%s replaces %s with %s which is not newer than the selected version.",
+				replace.Offender.Path,
+				replace.Original,
+				sarifOverride(replace),
+			)))
+		}
+	}
+
+	output := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "gomod",
+				InformationURI: "https://github.com/Helcaraxan/gomod",
+				Rules:          sarifRules,
+			}},
+			Results: append([]sarifResult{}, results...),
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("failed to write replacements as SARIF: %v", err)
+	}
+	return nil
+}
+
+// SourceRoot returns the root of the version-controlled repository containing the given directory,
+// which is the nearest directory including a '.git' entry. When the directory is not part of such a
+// repository it is returned as is.
+func SourceRoot(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	for current := absDir; ; current = filepath.Dir(current) {
+		if _, err = os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		if filepath.Dir(current) == current {
+			return dir
+		}
+	}
+}
+
+func sarifReplaceResult(root string, ruleID string, level string, replace Replacement, message string) sarifResult {
+	result := sarifResult{
+		RuleID:  ruleID,
+		Level:   level,
+		Message: sarifMessage{Text: message},
+	}
+	uri := sarifURI(root, replace.Offender.GoMod)
+	if uri == "" {
+		return result
+	}
+	location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri, URIBaseID: sarifSrcRoot}}
+	if replace.Line > 0 {
+		location.Region = &sarifRegion{StartLine: replace.Line}
+	}
+	result.Locations = []sarifLocation{{PhysicalLocation: location}}
+	return result
+}
+
+// sarifURI returns the path of the given go.mod file relative to 'root' in URI form, or an empty
+// string if the file is unknown or lies outside of 'root'.
+func sarifURI(root string, goModPath string) string {
+	if goModPath == "" {
+		return ""
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return ""
+	}
+	absGoMod, err := filepath.Abs(goModPath)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(absRoot, absGoMod)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+func sarifOverride(replace Replacement) string {
+	if replace.Version == "" {
+		return replace.Override
+	}
+	return replace.Override + "@" + replace.Version
+}
@@ -0,0 +1,98 @@
+package reveal
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_WriteSARIF(t *testing.T) {
+	pinB := Replacement{Offender: moduleA, Original: "originalB", Override: "originalB", Version: "v1.0.0", Line: 5}
+	replacements := &Replacements{
+		main: "test-module",
+		topLevel: map[string]Replacement{
+			"originalA": {Original: "originalA", Override: "overrideA"},
+			"originalB": {Original: "originalB", Override: "originalB", Version: "v1.0.0"},
+		},
+		replacedModules: []string{"originalA", "originalB", "originalC"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {atLine(replaceA, 3), replaceE},
+			"originalB": {pinB},
+			"originalC": {atLine(replaceC, 7)},
+		},
+	}
+	graph := depgraph.NewGraph(nil, &depgraph.Module{Main: true, Path: "test-module"})
+	graph.AddNode(&depgraph.Module{Path: "originalA", Version: "v1.5.0"})
+	graph.AddNode(&depgraph.Module{Path: "originalB", Version: "v1.2.0"})
+
+	writer := &strings.Builder{}
+	require.NoError(t, replacements.WriteSARIF(writer, graph, "testdata"))
+
+	var output sarifLog
+	require.NoError(t, json.Unmarshal([]byte(writer.String()), &output))
+	assert.Equal(t, "2.1.0", output.Version)
+	require.Len(t, output.Runs, 1)
+	assert.Equal(t, sarifRules, output.Runs[0].Tool.Driver.Rules)
+
+	var rules, uris []string
+	for _, result := range output.Runs[0].Results {
+		rules = append(rules, result.RuleID)
+		for _, location := range result.Locations {
+			uris = append(uris, location.PhysicalLocation.ArtifactLocation.URI)
+			assert.Equal(t, "%SRCROOT%", location.PhysicalLocation.ArtifactLocation.URIBaseID)
+		}
+	}
+	assert.Equal(t, []string{"unmatched-top-level", "conflicting-replaces", "conflicting-replaces", "stale-replace"}, rules)
+	assert.Equal(t, []string{"moduleA/go.mod", "moduleA/go.mod"}, uris, "Should only locate results in go.mod files below the root.")
+	assert.Equal(t, &sarifRegion{StartLine: 7}, output.Runs[0].Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Empty(t, output.Runs[0].Results[1].Locations, "Should omit the location of unknown go.mod files.")
+	assert.Contains(t, output.Runs[0].Results[3].Message.Text, "originalB@v1.0.0")
+
+	writer = &strings.Builder{}
+	require.NoError(t, replacements.WriteSARIF(writer, graph, filepath.Join("testdata", "mainModule")))
+	assert.NotContains(t, writer.String(), `"locations"`, "Should omit the location of go.mod files outside of the root.")
+
+	writer = &strings.Builder{}
+	require.NoError(t, (&Replacements{main: "test-module"}).WriteSARIF(writer, nil, "."))
+	assert.Contains(t, writer.String(), `"results": []`, "Should write an empty list of results.")
+}
+
+func Test_SourceRoot(t *testing.T) {
+	repository, err := ioutil.TempDir("", "gomod-sarif")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(repository) }()
+	repository, err = filepath.EvalSymlinks(repository)
+	require.NoError(t, err)
+
+	nested := filepath.Join(repository, "tools", "sub")
+	require.NoError(t, os.MkdirAll(nested, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(repository, ".git"), 0755))
+	assert.Equal(t, repository, SourceRoot(nested), "Should find the repository root above a nested module.")
+	assert.Equal(t, repository, SourceRoot(repository))
+
+	nestedModule := &depgraph.Module{Path: "nested-module", GoMod: filepath.Join(nested, "go.mod")}
+	replacements := &Replacements{
+		main:            "nested-module",
+		topLevel:        map[string]Replacement{},
+		replacedModules: []string{"originalA"},
+		originToReplace: map[string][]Replacement{
+			"originalA": {{Offender: nestedModule, Original: "originalA", Override: "overrideA", Version: "v1.0.0", Line: 3}},
+		},
+	}
+	writer := &strings.Builder{}
+	require.NoError(t, replacements.WriteSARIF(writer, nil, SourceRoot(nested)))
+
+	var output sarifLog
+	require.NoError(t, json.Unmarshal([]byte(writer.String()), &output))
+	require.Len(t, output.Runs[0].Results, 1)
+	require.Len(t, output.Runs[0].Results[0].Locations, 1)
+	assert.Equal(t, "tools/sub/go.mod", output.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
@@ -0,0 +1,10 @@
+module dependency
+
+go 1.12
+
+replace (
+	originalB => overrideB v1.0.0
+	originalC v0.1.0 => ./overrideC
+	originalC v0.1.0 => ./overrideC-bis
+	originalC v0.2.0 => ./overrideC
+)
@@ -0,0 +1,10 @@
+module test/duplicates
+
+go 1.12
+
+replace originalA => overrideA v1.0.0
+
+replace (
+	originalA => overrideA v1.1.0
+	originalB => overrideB v1.0.0
+)
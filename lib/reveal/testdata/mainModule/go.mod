@@ -8,3 +8,8 @@ require (
 )
 
 replace module/foo => module/foo-bis v1.0.0
+
+exclude (
+    module/bar v1.0.1
+    originalB v0.9.1
+)
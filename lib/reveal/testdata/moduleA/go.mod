@@ -14,3 +14,5 @@ replace (
     originalB => overrideB v1.0.0
     originalC => ./overrideC
 )
+
+exclude originalB v0.9.0
@@ -0,0 +1,3 @@
+module overrideC
+
+go 1.12
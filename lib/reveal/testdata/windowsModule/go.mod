@@ -0,0 +1,5 @@
+﻿module moduleW
+
+require originalC v0.2.3
+
+replace originalC => ./overrideC
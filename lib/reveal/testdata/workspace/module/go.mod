@@ -0,0 +1,3 @@
+module test/workspace/module
+
+go 1.18
@@ -0,0 +1,166 @@
+package reveal
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+// watchDebounce is the delay without further changes after which the replacements are printed
+// again. Editors often write a file several times in a row when saving it.
+const watchDebounce = 250 * time.Millisecond
+
+// Watch finds the replacements of the DepGraph with the given options and passes them to 'render',
+// and does so again each time one of the editable go.mod files, or the go.work file, from which
+// they originate changes on disk. Successive changes within a short delay result in a single update.
+// Failures to determine or render the replacements after a change are logged and do not stop the
+// watch, which runs until the context is canceled. The 'logger' parameter can be 'nil' which will
+// result in no output or logging information to be provided.
+func Watch(
+	ctx context.Context,
+	logger *logrus.Logger,
+	graph *depgraph.DepGraph,
+	opts FindOptions,
+	render func(*Replacements) error,
+) error {
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(ioutil.Discard)
+	}
+
+	files, err := watchedFiles(logger, graph, opts)
+	if err != nil {
+		return fmt.Errorf("could not list the go.mod files to watch: %v", err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not watch go.mod files: %v", err)
+	}
+	// Directories are watched instead of the files themselves as editors may replace a file when
+	// saving it, which would silently end a watch on the original file.
+	for _, dir := range watchedDirs(files) {
+		logger.Debugf("Watching %q for changes.", dir)
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return fmt.Errorf("could not watch %q: %v", dir, err)
+		}
+	}
+
+	run := func() error {
+		replacements, findErr := FindReplacementsWithOptions(logger, graph, opts)
+		if findErr != nil {
+			return findErr
+		}
+		return render(replacements)
+	}
+	err = watch(ctx, logger, watcher.Events, watcher.Errors, files, watchDebounce, run)
+	_ = watcher.Close()
+	return err
+}
+
+// watch calls 'run' once and then again each time that a relevant event has been received and no
+// other one followed within the 'debounce' delay. Only errors of the initial call are returned.
+func watch(
+	ctx context.Context,
+	logger *logrus.Logger,
+	events <-chan fsnotify.Event,
+	errs <-chan error,
+	files map[string]struct{},
+	debounce time.Duration,
+	run func() error,
+) error {
+	if err := run(); err != nil {
+		return err
+	}
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if _, relevant := files[filepath.Clean(event.Name)]; !relevant {
+				continue
+			}
+			logger.Debugf("Detected change of %q.", event.Name)
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(debounce)
+			fire = timer.C
+		case err, ok := <-errs:
+			if !ok {
+				return nil
+			}
+			logger.WithError(err).Warn("Error while watching go.mod files.")
+		case <-fire:
+			timer, fire = nil, nil
+			if err := run(); err != nil {
+				logger.WithError(err).Error("Failed to reveal replacements.")
+			}
+		}
+	}
+}
+
+// watchedFiles returns the cleaned paths of the go.mod files that are read when finding the
+// replacements of the DepGraph with the given options and that can be edited. These are the files
+// of the main module, of the other modules of its workspace and of modules that are replaced by a
+// local directory, as well as the vendored ones if requested. The go.mod files of the other modules
+// reside in the read-only module cache and are not watched. The go.work file that applies to the
+// main module is watched as well or, if there is none, the one next to the main module's go.mod so
+// that its creation is noticed.
+func watchedFiles(logger *logrus.Logger, graph *depgraph.DepGraph, opts FindOptions) (map[string]struct{}, error) {
+	files := map[string]struct{}{}
+	for _, node := range graph.Nodes() {
+		if !node.Module.Main && node.Module.Version != "" && !node.Module.IsLocalReplace() {
+			continue
+		}
+		if _, goModPath := findGoModFile(logger, node.Module); goModPath != "" {
+			files[filepath.Clean(goModPath)] = struct{}{}
+		}
+	}
+	if goWorkPath := findGoWorkFile(logger, graph.Main().Module); goWorkPath != "" {
+		files[filepath.Clean(goWorkPath)] = struct{}{}
+	} else if main := graph.Main().Module; main.GoMod != "" {
+		files[filepath.Join(filepath.Dir(main.GoMod), "go.work")] = struct{}{}
+	}
+	if opts.IncludeVendor {
+		_, vendored, err := vendoredGoModFiles(logger, graph.Main().Module)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range vendored {
+			files[filepath.Clean(path)] = struct{}{}
+		}
+	}
+	return files, nil
+}
+
+// watchedDirs returns the sorted and distinct directories containing the specified files.
+func watchedDirs(files map[string]struct{}) []string {
+	unique := map[string]struct{}{}
+	for file := range files {
+		unique[filepath.Dir(file)] = struct{}{}
+	}
+	dirs := make([]string, 0, len(unique))
+	for dir := range unique {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
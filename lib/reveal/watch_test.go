@@ -0,0 +1,148 @@
+package reveal
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Helcaraxan/gomod/lib/depgraph"
+)
+
+func Test_Watch(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	events := make(chan fsnotify.Event)
+	errs := make(chan error)
+	files := map[string]struct{}{"moduleA/go.mod": {}}
+
+	runs := make(chan struct{}, 10)
+	run := func() error {
+		runs <- struct{}{}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- watch(ctx, logger, events, errs, files, 50*time.Millisecond, run) }()
+
+	<-runs
+	events <- fsnotify.Event{Name: "moduleB/go.mod", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "moduleA/go.mod", Op: fsnotify.Write}
+	events <- fsnotify.Event{Name: "./moduleA/go.mod", Op: fsnotify.Write}
+	errs <- errors.New("watch failure")
+	select {
+	case <-runs:
+	case <-time.After(time.Second):
+		assert.Fail(t, "Should have run again after a change.")
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, runs, "Should have run only once for successive changes.")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func Test_WatchInitialFailure(t *testing.T) {
+	err := watch(context.Background(), logrus.New(), nil, nil, nil, 0, func() error {
+		return errors.New("failure")
+	})
+	assert.Error(t, err, "Should return errors of the initial run.")
+}
+
+func Test_WatchedDirs(t *testing.T) {
+	files := map[string]struct{}{
+		"moduleB/go.mod":  {},
+		"moduleA/go.mod":  {},
+		"moduleA/go.work": {},
+	}
+	assert.Equal(t, []string{"moduleA", "moduleB"}, watchedDirs(files))
+}
+
+// watchTestModule creates a main module with a vendored go.mod file in a temporary directory and
+// returns the directory together with a graph for the module.
+func watchTestModule(t *testing.T) (string, *depgraph.DepGraph) {
+	dir, err := ioutil.TempDir("", "gomod-watch")
+	require.NoError(t, err)
+	for path, content := range map[string]string{
+		"go.mod":            "module test/watch\n\nrequire dep v1.0.0\n",
+		"vendor/dep/go.mod": "module dep\n\nreplace originalA => overrideA v1.0.0\n",
+		"fork/go.mod":       "module forked\n",
+		"cache/dep/go.mod":  "module dep\n",
+	} {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+
+	graph := depgraph.NewGraph(nil, &depgraph.Module{Main: true, Path: "test/watch", GoMod: filepath.Join(dir, "go.mod")})
+	graph.AddNode(&depgraph.Module{Path: "dep", Version: "v1.0.0", GoMod: filepath.Join(dir, "cache", "dep", "go.mod")})
+	graph.AddNode(&depgraph.Module{
+		Path:    "forked",
+		Version: "v1.0.0",
+		Replace: &depgraph.Module{Path: "./fork", GoMod: filepath.Join(dir, "fork", "go.mod")},
+	})
+	return dir, graph
+}
+
+func Test_WatchedFiles(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	dir, graph := watchTestModule(t)
+	defer os.RemoveAll(dir)
+
+	files, err := watchedFiles(logger, graph, FindOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{
+		filepath.Join(dir, "go.mod"):         {},
+		filepath.Join(dir, "go.work"):        {},
+		filepath.Join(dir, "fork", "go.mod"): {},
+	}, files, "Should not watch the go.mod files in the module cache.")
+
+	files, err = watchedFiles(logger, graph, FindOptions{IncludeVendor: true})
+	require.NoError(t, err)
+	assert.Contains(t, files, filepath.Join(dir, "vendor", "dep", "go.mod"), "Should watch vendored go.mod files.")
+}
+
+func Test_WatchVendored(t *testing.T) {
+	dir, graph := watchTestModule(t)
+	defer os.RemoveAll(dir)
+
+	renders := make(chan []string, 10)
+	render := func(replacements *Replacements) error {
+		renders <- replacements.replacedModules
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- Watch(ctx, nil, graph, FindOptions{IncludeVendor: true}, render) }()
+
+	select {
+	case replaced := <-renders:
+		assert.Equal(t, []string{"originalA"}, replaced)
+	case err := <-done:
+		require.Fail(t, "Should not have stopped watching.", "%v", err)
+	}
+	vendored := filepath.Join(dir, "vendor", "dep", "go.mod")
+	content := "module dep\n\nreplace (\n\toriginalA => overrideA v1.0.0\n\toriginalB => overrideB v1.0.0\n)\n"
+	require.NoError(t, ioutil.WriteFile(vendored, []byte(content), 0644))
+	select {
+	case replaced := <-renders:
+		assert.Equal(t, []string{"originalA", "originalB"}, replaced, "Should find the new vendored replacement.")
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Should have run again after a change of a vendored go.mod file.")
+	}
+
+	cancel()
+	assert.NoError(t, <-done)
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 
@@ -142,6 +147,8 @@ type graphArgs struct {
 
 	shared       bool
 	dependencies []string
+	prefix       string
+	toolchain    bool
 }
 
 func initGraphCmd(cArgs *commonArgs) *cobra.Command {
@@ -165,14 +172,16 @@ func initGraphCmd(cArgs *commonArgs) *cobra.Command {
 	graphCmd.Flags().BoolVarP(&cmdArgs.annotate, "annotate", "a", false, "Annotate the graph's nodes and edges with version information")
 	graphCmd.Flags().BoolVarP(&cmdArgs.force, "force", "f", false, "Overwrite any existing files")
 	graphCmd.Flags().StringVarP(&cmdArgs.outputPath, "output", "o", "", "If set dump the output to this location")
-	graphCmd.Flags().StringVarP(&cmdArgs.outputFormat, "format", "F", "", "Output format for any image file (pdf, png, gif, ...)")
+	graphCmd.Flags().StringVarP(&cmdArgs.outputFormat, "format", "F", "", "Output format for any image file (pdf, png, gif, ...) or a text format (json, mermaid, graphml, csv, d2, html) for non-visual output")
 
-	graphCmd.Flags().Lookup("output").Annotations = map[string][]string{cobra.BashCompFilenameExt: {"dot", "gif", "pdf", "png", "ps"}}
+	graphCmd.Flags().Lookup("output").Annotations = map[string][]string{cobra.BashCompFilenameExt: {"csv", "d2", "dot", "gif", "graphml", "html", "json", "mmd", "pdf", "png", "ps"}}
 	graphCmd.Flags().Lookup("format").Annotations = map[string][]string{cobra.BashCompCustom: {"__gomod_graph_format"}}
 
 	// Flags controlling graph filtering.
 	graphCmd.Flags().BoolVarP(&cmdArgs.shared, "shared", "s", false, "Filter out unshared dependencies (i.e. only required by one Go module)")
 	graphCmd.Flags().StringSliceVarP(&cmdArgs.dependencies, "dependencies", "d", nil, "Dependency for which to show the dependency graph")
+	graphCmd.Flags().StringVarP(&cmdArgs.prefix, "prefix", "p", "", "Only show modules whose path starts with the given prefix")
+	graphCmd.Flags().BoolVar(&cmdArgs.toolchain, "no-toolchain", false, "Remove the 'go' and 'toolchain' entries, and any other module without a version")
 
 	graphCmd.Flags().Lookup("dependencies").Annotations = map[string][]string{cobra.BashCompCustom: {"__gomod_graph_dependencies"}}
 
@@ -188,6 +197,9 @@ func runGraphCmd(args *graphArgs) error {
 	if err != nil {
 		return err
 	}
+	if args.toolchain {
+		graph = graph.StripToolchainNodes()
+	}
 
 	if args.shared {
 		graph = graph.PruneUnsharedDeps()
@@ -202,6 +214,9 @@ func runGraphCmd(args *graphArgs) error {
 		}
 		graph = graph.SubGraph(versionFilter)
 	}
+	if args.prefix != "" {
+		graph = graph.FilterByPrefix(args.prefix)
+	}
 	return printResult(graph, args)
 }
 
@@ -236,8 +251,12 @@ func runAnalyseCmd(args *analyseArgs) error {
 
 type revealArgs struct {
 	*commonArgs
-	sources []string
-	targets []string
+	sources  []string
+	targets  []string
+	format   string
+	strict   bool
+	baseline string
+	watch    bool
 }
 
 func initRevealCmd(cArgs *commonArgs) *cobra.Command {
@@ -255,20 +274,104 @@ func initRevealCmd(cArgs *commonArgs) *cobra.Command {
 
 	revealCmd.Flags().StringSliceVarP(&cmdArgs.sources, "sources", "s", nil, "Filter all places that are replacing dependencies.")
 	revealCmd.Flags().StringSliceVarP(&cmdArgs.targets, "targets", "t", nil, "Filter all places that replace the specified modules.")
+	revealCmd.Flags().StringVarP(&cmdArgs.format, "format", "F", "text", "Output format for the replacements (text, json, tsv, tree, snippet, baseline, sarif).")
+	revealCmd.Flags().BoolVar(&cmdArgs.strict, "strict", false, "Exit with an error if any replace is not matched by a top-level replace.")
+	revealCmd.Flags().StringVar(&cmdArgs.baseline, "baseline", "", "Exit with an error if the replaces differ from the specified baseline file.")
+	revealCmd.Flags().BoolVar(&cmdArgs.watch, "watch", false, "Print the replacements again each time a relevant go.mod file changes.")
 
 	return revealCmd
 }
 
 func runRevealCmd(args *revealArgs) error {
+	if args.watch && (args.strict || args.baseline != "") {
+		return errors.New("the --watch flag can not be combined with --strict or --baseline")
+	}
+
 	graph, err := depgraph.GetDepGraph(args.logger, args.quiet)
 	if err != nil {
 		return err
 	}
+	if args.watch {
+		ctx, cancel := context.WithCancel(context.Background())
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		go func() {
+			<-interrupts
+			cancel()
+		}()
+		return reveal.Watch(ctx, args.logger, graph, reveal.FindOptions{}, func(replacements *reveal.Replacements) error {
+			_, renderErr := renderReplacements(args, graph, replacements)
+			return renderErr
+		})
+	}
 	replacements, err := reveal.FindReplacements(args.logger, graph)
 	if err != nil {
 		return err
 	}
-	return replacements.Print(args.logger, os.Stdout, args.sources, args.targets)
+	filtered, err := renderReplacements(args, graph, replacements)
+	if err != nil {
+		return err
+	}
+
+	if unmatched := filtered.UnmatchedTopLevel(); args.strict && len(unmatched) > 0 {
+		return fmt.Errorf("found %d replace(s) that are not matched by a top-level replace", len(unmatched))
+	}
+	if args.baseline != "" {
+		return checkBaseline(args.logger, args.baseline, filtered)
+	}
+	return nil
+}
+
+// renderReplacements applies the requested filters to the replacements and writes them to stdout
+// in the requested format. It returns the filtered replacements.
+func renderReplacements(args *revealArgs, graph *depgraph.DepGraph, replacements *reveal.Replacements) (*reveal.Replacements, error) {
+	filtered := replacements.FilterOnOffendingModule(args.sources).FilterOnReplacedModule(args.targets)
+	var err error
+	switch args.format {
+	case "text":
+		err = filtered.Print(args.logger, os.Stdout, nil, nil, reveal.PrintOpts{})
+	case "json":
+		err = filtered.WriteJSON(os.Stdout)
+	case "tsv":
+		err = filtered.PrintTSV(os.Stdout)
+	case "tree":
+		err = filtered.PrintTree(os.Stdout)
+	case "snippet":
+		err = filtered.TopLevelSnippet(os.Stdout)
+	case "baseline":
+		err = filtered.WriteBaseline(os.Stdout)
+	case "sarif":
+		root := "."
+		if goMod := graph.MainModule().GoMod; goMod != "" {
+			root = reveal.SourceRoot(filepath.Dir(goMod))
+		}
+		err = filtered.WriteSARIF(os.Stdout, graph, root)
+	default:
+		err = fmt.Errorf("unknown output format %q", args.format)
+	}
+	return filtered, err
+}
+
+func checkBaseline(logger *logrus.Logger, baselinePath string, replacements *reveal.Replacements) error {
+	baseline, err := ioutil.ReadFile(baselinePath)
+	if err != nil {
+		return fmt.Errorf("could not read baseline: %v", err)
+	}
+
+	added, removed, err := reveal.CompareBaseline(bytes.NewReader(baseline), replacements)
+	if err != nil {
+		return err
+	}
+	for _, replacement := range added {
+		logger.Errorf("New replace of %q by %q in %q.", replacement.Original, replacement.Override, replacement.Offender.Path)
+	}
+	for _, replacement := range removed {
+		logger.Errorf("Removed replace of %q by %q in %q.", replacement.Original, replacement.Override, replacement.Offender.Path)
+	}
+	if len(added)+len(removed) > 0 {
+		return fmt.Errorf("found %d replace(s) that differ from the baseline in %q", len(added)+len(removed), baselinePath)
+	}
+	return nil
 }
 
 func checkToolDependencies(logger *logrus.Logger) error {